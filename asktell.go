@@ -0,0 +1,211 @@
+package pso
+
+import (
+	"errors"
+	"sync"
+)
+
+//AskMode is the flag for how an AskTell hands out candidates and updates the swarm.
+type AskMode int32
+
+//Generational sets Generational AskMode.  Ask hands out every particle and the swarm is updated with SyncUpdate
+//once every candidate of the generation has been told.
+func (a *AskMode) Generational() AskMode { *a = AskMode(1); return *a }
+
+//SteadyState sets SteadyState AskMode.  Ask hands out the particles that aren't being evaluated and each Tell
+//updates that particle right away like AsyncUpdate.
+func (a *AskMode) SteadyState() AskMode { *a = AskMode(2); return *a }
+
+//Ticket identifies a Candidate handed out by Ask.
+type Ticket uint64
+
+//Errors returned by Tell.
+var (
+	//ErrUnknownTicket is returned for a ticket that was never handed out.
+	ErrUnknownTicket = errors.New("Ticket was never handed out")
+	//ErrDuplicateTicket is returned for a ticket that was already told.
+	ErrDuplicateTicket = errors.New("Ticket was already told")
+	//ErrStaleTicket is returned when the particle of a ticket moved since the ticket was handed out,
+	//or when a particle moved while Optimize, OptimizeContext or Step was evaluating it.
+	//In Generational mode the whole generation is dropped and Ask can be called again.
+	ErrStaleTicket = errors.New("Particle moved since ticket was handed out")
+)
+
+//Candidate is a position to evaluate. Pass its Ticket to Tell with the fitness of Position.
+//
+//Position is a copy.  The AskTell keeps its own copy so the personal and global bests are always the position that was told,
+//even if the caller changes Position.
+type Candidate[T Float] struct {
+	Ticket   Ticket
+	Particle int
+	Position []T
+}
+
+//AskTell drives a swarm whose evaluations happen somewhere else, like a queue on a cluster.
+//Ask returns candidates to evaluate and Tell gives their fitness back.  It is safe for concurrent use.
+type AskTell[T Float] struct {
+	s    *Swarm[T]
+	mode AskMode
+	mux  sync.Mutex
+	next Ticket
+
+	//pending maps the tickets that are out to their particles and positions.
+	pending map[Ticket]ticket[T]
+	//stale is the first ticket that wasn't dropped.
+	stale Ticket
+	//busy is true for particles that are out.
+	busy []bool
+
+	//records and positions of the generation in Generational mode.
+	records   []Record[T]
+	positions [][]T
+	remaining int
+}
+
+type ticket[T Float] struct {
+	particle int
+	position []T
+}
+
+//NewAskTell returns an AskTell for s.  s needs to be set up with one of its Set methods first.
+func NewAskTell[T Float](s *Swarm[T], mode AskMode) *AskTell[T] {
+	return &AskTell[T]{
+		s:       s,
+		mode:    mode,
+		pending: make(map[Ticket]ticket[T]),
+	}
+}
+
+//Ask returns the candidates to evaluate.
+//
+//In Generational mode it returns a candidate for every particle. It returns an error if the last generation hasn't been told yet.
+//In SteadyState mode it returns a candidate for every particle that isn't out already.  That can be none.
+func (a *AskTell[T]) Ask() ([]Candidate[T], error) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	var m AskMode
+	positions := a.s.positions()
+	n := len(positions)
+	if len(a.busy) != n {
+		if len(a.pending) > 0 {
+			return nil, errors.New("Number of particles changed while candidates are out")
+		}
+		a.busy = make([]bool, n)
+	}
+	if a.mode == m.Generational() {
+		if a.remaining > 0 {
+			return nil, errors.New("Generation still has candidates out")
+		}
+		a.records = make([]Record[T], n)
+		a.positions = make([][]T, n)
+		a.remaining = n
+	}
+	var candidates []Candidate[T]
+	for i := 0; i < n; i++ {
+		if a.busy[i] {
+			continue
+		}
+		a.next++
+		a.busy[i] = true
+		a.pending[a.next] = ticket[T]{particle: i, position: positions[i]}
+		candidates = append(candidates, Candidate[T]{
+			Ticket:   a.next,
+			Particle: i,
+			Position: append([]T(nil), positions[i]...),
+		})
+	}
+	return candidates, nil
+}
+
+//Tell gives back the fitness of the candidate with ticket.
+//
+//It returns ErrUnknownTicket, ErrDuplicateTicket or ErrStaleTicket if the fitness can't be matched to where the particle is.
+func (a *AskTell[T]) Tell(ticket Ticket, fitness T) error {
+	return a.tell(ticket, Record[T]{Fitness: fitness})
+}
+
+//TellConstrained gives back the fitness and constraint violation of the candidate with ticket.  See AsyncUpdateConstrained.
+func (a *AskTell[T]) TellConstrained(ticket Ticket, fitness, violation T) error {
+	return a.tell(ticket, Record[T]{Fitness: fitness, Violation: violation})
+}
+
+//TellRecord gives back the whole Record of the candidate with ticket, like when a Comparator uses Keys.
+func (a *AskTell[T]) TellRecord(ticket Ticket, r Record[T]) error {
+	r.Keys = append([]T(nil), r.Keys...)
+	return a.tell(ticket, r)
+}
+
+func (a *AskTell[T]) tell(id Ticket, r Record[T]) error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	t, ok := a.pending[id]
+	switch {
+	case ok:
+	case id == 0 || id > a.next:
+		return ErrUnknownTicket
+	case id < a.stale:
+		return ErrStaleTicket
+	default:
+		return ErrDuplicateTicket
+	}
+	delete(a.pending, id)
+	i := t.particle
+	var m AskMode
+	if a.mode != m.Generational() {
+		if i < len(a.busy) {
+			a.busy[i] = false
+		}
+		if a.s.moved(i, t.position) {
+			return ErrStaleTicket
+		}
+		return a.s.asyncupdate(i, r, t.position)
+	}
+	if a.s.moved(i, t.position) {
+		for id := range a.pending {
+			delete(a.pending, id)
+		}
+		for j := range a.busy {
+			a.busy[j] = false
+		}
+		a.remaining = 0
+		a.stale = a.next + 1
+		return ErrStaleTicket
+	}
+	a.records[i] = r
+	a.positions[i] = t.position
+	a.remaining--
+	if a.remaining > 0 {
+		return nil
+	}
+	for j := range a.busy {
+		a.busy[j] = false
+	}
+	return a.s.syncupdate(a.records, a.positions, false)
+}
+
+//moved returns true if particle i isn't at position anymore.
+func (s *Swarm[T]) moved(i int, position []T) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return i >= len(s.particles) || !equal(position, s.particles[i].copyposition())
+}
+
+//equal returns true if a and b are the same length and hold the same values.
+func equal[T Float](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//Pending returns the number of candidates that are out.
+func (a *AskTell[T]) Pending() int {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	return len(a.pending)
+}
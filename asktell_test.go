@@ -0,0 +1,175 @@
+package pso
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+//asktellswarm returns a swarm for the Ask/Tell tests.
+func asktellswarm(t *testing.T) *Swarm64 {
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(6, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func asktellsphere(x []float64) float64 { return x[0]*x[0] + x[1]*x[1] }
+
+//TestAskTellGenerational checks that a generation told out of order moves the swarm like SyncUpdate.
+func TestAskTellGenerational(t *testing.T) {
+	s, twin := asktellswarm(t), asktellswarm(t)
+	var m AskMode
+	a := NewAskTell(s, m.Generational())
+	for k := 0; k < 20; k++ {
+		candidates, err := a.Ask()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(candidates) != 6 {
+			t.Fatalf("Ask returned %d candidates, want 6", len(candidates))
+		}
+		if _, err = a.Ask(); err == nil {
+			t.Fatal("Ask with the generation still out returned no error")
+		}
+		positions := twin.positions()
+		fitnesses := make([]float64, len(positions))
+		for i := range positions {
+			fitnesses[i] = asktellsphere(positions[i])
+		}
+		for i := len(candidates) - 1; i >= 0; i-- {
+			c := candidates[i]
+			if !equal(c.Position, positions[c.Particle]) {
+				t.Fatalf("candidate of particle %d is at %v, want %v", c.Particle, c.Position, positions[c.Particle])
+			}
+			err = a.Tell(c.Ticket, asktellsphere(c.Position))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = a.Tell(candidates[0].Ticket, 0); err == nil {
+			t.Fatal("Tell of a ticket that was already told returned no error")
+		}
+		err = twin.SyncUpdate(fitnesses)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Tell(1000, 0); err == nil {
+		t.Fatal("Tell of a ticket that was never handed out returned no error")
+	}
+	sameswarm(t, s, twin)
+}
+
+//TestAskTellSteadyState checks that Ask only hands out particles that aren't out and each Tell moves the swarm like AsyncUpdate.
+func TestAskTellSteadyState(t *testing.T) {
+	s, twin := asktellswarm(t), asktellswarm(t)
+	var m AskMode
+	a := NewAskTell(s, m.SteadyState())
+	out, err := a.Ask()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 6 {
+		t.Fatalf("first Ask returned %d candidates, want 6", len(out))
+	}
+	for k := 0; k < 60; k++ {
+		c := out[k%len(out)]
+		out = append(out[:k%len(out)], out[k%len(out)+1:]...)
+		err = a.Tell(c.Ticket, asktellsphere(c.Position))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = twin.AsyncUpdate(c.Particle, asktellsphere(twin.ParticlePosition(c.Particle)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		more, err := a.Ask()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(more) != 1 || more[0].Particle != c.Particle {
+			t.Fatalf("Ask after telling particle %d returned %v, want only that particle", c.Particle, more)
+		}
+		out = append(out, more...)
+		if a.Pending() != 6 {
+			t.Fatalf("%d candidates out, want 6", a.Pending())
+		}
+	}
+	sameswarm(t, s, twin)
+}
+
+//TestStaleOptimize checks that Optimize, Step and OptimizeContext don't record fitnesses for particles that were moved while
+//they were evaluated, and that an objective changing its slice doesn't look like a moved particle.
+func TestStaleOptimize(t *testing.T) {
+	newswarm := func() *Swarm64 {
+		s := CreateSwarm64(5)
+		err := s.SetConstantInertia(6, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	sphere := func(x []float64) float64 {
+		sum := x[0]*x[0] + x[1]*x[1]
+		x[0], x[1] = 100, 100
+		return sum
+	}
+	budget := Budget64{MaxIterations: 5}
+	s := newswarm()
+	_, err := s.Optimize(sphere, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEvaluator(func(ctx context.Context, x []float64) (float64, error) { return sphere(x), nil }, 2)
+	err = s.Step(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.OptimizeContext(context.Background(), e, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s = newswarm()
+	moving := func(x []float64) float64 {
+		s.ResetParticle(0)
+		return sphere(x)
+	}
+	_, err = s.Optimize(moving, budget)
+	if !errors.Is(err, ErrStaleTicket) {
+		t.Fatalf("Optimize returned %v, want ErrStaleTicket", err)
+	}
+	if s.HasBest() {
+		t.Fatal("Optimize recorded a best for a moved particle")
+	}
+	e = NewEvaluator(func(ctx context.Context, x []float64) (float64, error) { return moving(x), nil }, 2)
+	err = s.Step(context.Background(), e)
+	if !errors.Is(err, ErrStaleTicket) {
+		t.Fatalf("Step returned %v, want ErrStaleTicket", err)
+	}
+	_, err = s.OptimizeContext(context.Background(), e, budget)
+	if !errors.Is(err, ErrStaleTicket) {
+		t.Fatalf("OptimizeContext returned %v, want ErrStaleTicket", err)
+	}
+}
+
+//TestEqual checks that positions of different lengths are never equal.
+func TestEqual(t *testing.T) {
+	for _, c := range []struct {
+		a, b []float64
+		want bool
+	}{
+		{[]float64{1, 2}, []float64{1, 2}, true},
+		{[]float64{1, 2}, []float64{1, 3}, false},
+		{[]float64{1}, []float64{1, 2}, false},
+		{[]float64{1, 2}, []float64{1}, false},
+		{nil, nil, true},
+	} {
+		if got := equal(c.a, c.b); got != c.want {
+			t.Fatalf("equal(%v, %v) is %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Package benchmarks has the classic test functions used to check how well a particle swarm optimizes.
+
+Every function is generic so it can be passed straight to Swarm32 or Swarm64 as an objective.
+
+	s := pso.CreateSwarm64(1)
+	s.SetConstriction(30, 10, 2.05, 2.05, 1, -5.12, 5.12)
+	r, err := s.Minimize(benchmarks.Rastrigin[float64], pso.Budget64{MaxIterations: 1000})
+
+All of the functions are minimized.  Function holds what is known about each one.
+*/
+package benchmarks
+
+import (
+	"math"
+
+	"github.com/dereklstinson/pso"
+)
+
+//Function describes a benchmark function.
+type Function struct {
+	Name       string
+	Lower      float64 //Recommended lower bound for every dimension
+	Upper      float64 //Recommended upper bound for every dimension
+	Separable  bool
+	Multimodal bool
+	F32        func([]float32) float32
+	F64        func([]float64) float64
+	optimum    func(dims int) []float64
+	value      func(dims int) float64
+}
+
+//Optimum returns the location of the global minimum for dims dimensions.
+func (f Function) Optimum(dims int) []float64 {
+	return f.optimum(dims)
+}
+
+//OptimumValue returns the value of the global minimum for dims dimensions.
+func (f Function) OptimumValue(dims int) float64 {
+	return f.value(dims)
+}
+
+//Bounds returns the recommended bounds for dims dimensions. They can be passed to SetBounds.
+func (f Function) Bounds(dims int) (lower, upper []float64) {
+	lower = make([]float64, dims)
+	upper = make([]float64, dims)
+	for i := range lower {
+		lower[i] = f.Lower
+		upper[i] = f.Upper
+	}
+	return lower, upper
+}
+
+//Bounds32 is Bounds at float32 precision.
+func (f Function) Bounds32(dims int) (lower, upper []float32) {
+	l, u := f.Bounds(dims)
+	lower = make([]float32, dims)
+	upper = make([]float32, dims)
+	for i := range l {
+		lower[i] = float32(l[i])
+		upper[i] = float32(u[i])
+	}
+	return lower, upper
+}
+
+//All returns every benchmark function.
+func All() []Function {
+	return []Function{
+		{Name: "Sphere", Lower: -5.12, Upper: 5.12, Separable: true, F32: Sphere[float32], F64: Sphere[float64], optimum: fill(0), value: constant(0)},
+		{Name: "Ellipsoid", Lower: -100, Upper: 100, Separable: true, F32: Ellipsoid[float32], F64: Ellipsoid[float64], optimum: fill(0), value: constant(0)},
+		{Name: "Zakharov", Lower: -5, Upper: 10, F32: Zakharov[float32], F64: Zakharov[float64], optimum: fill(0), value: constant(0)},
+		{Name: "Rosenbrock", Lower: -5, Upper: 10, F32: Rosenbrock[float32], F64: Rosenbrock[float64], optimum: fill(1), value: constant(0)},
+		{Name: "Rastrigin", Lower: -5.12, Upper: 5.12, Separable: true, Multimodal: true, F32: Rastrigin[float32], F64: Rastrigin[float64], optimum: fill(0), value: constant(0)},
+		{Name: "Ackley", Lower: -32.768, Upper: 32.768, Multimodal: true, F32: Ackley[float32], F64: Ackley[float64], optimum: fill(0), value: constant(0)},
+		{Name: "Griewank", Lower: -600, Upper: 600, Multimodal: true, F32: Griewank[float32], F64: Griewank[float64], optimum: fill(0), value: constant(0)},
+		{Name: "Schwefel", Lower: -500, Upper: 500, Separable: true, Multimodal: true, F32: Schwefel[float32], F64: Schwefel[float64], optimum: fill(schwefelx), value: func(dims int) float64 { return Schwefel(fill(schwefelx)(dims)) }},
+		{Name: "Levy", Lower: -10, Upper: 10, Multimodal: true, F32: Levy[float32], F64: Levy[float64], optimum: fill(1), value: constant(0)},
+		{Name: "StyblinskiTang", Lower: -5, Upper: 5, Separable: true, Multimodal: true, F32: StyblinskiTang[float32], F64: StyblinskiTang[float64], optimum: fill(styblinskitangx), value: func(dims int) float64 { return StyblinskiTang(fill(styblinskitangx)(dims)) }},
+	}
+}
+
+//Lookup returns the benchmark function with name.
+func Lookup(name string) (Function, bool) {
+	for _, f := range All() {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Function{}, false
+}
+
+const (
+	schwefelx       = 420.9687462275036
+	styblinskitangx = -2.903534027771178
+)
+
+func fill(v float64) func(int) []float64 {
+	return func(dims int) []float64 {
+		x := make([]float64, dims)
+		for i := range x {
+			x[i] = v
+		}
+		return x
+	}
+}
+
+func constant(v float64) func(int) float64 {
+	return func(int) float64 { return v }
+}
+
+//Sphere is sum(x^2). Unimodal and separable.
+func Sphere[T pso.Float](x []T) T {
+	var f float64
+	for _, v := range x {
+		f += float64(v) * float64(v)
+	}
+	return T(f)
+}
+
+//Ellipsoid is the high conditioned elliptic function sum(1e6^((i-1)/(d-1))*x^2). Unimodal, separable and badly conditioned.
+func Ellipsoid[T pso.Float](x []T) T {
+	var f float64
+	for i, v := range x {
+		c := 1.0
+		if len(x) > 1 {
+			c = math.Pow(1e6, float64(i)/float64(len(x)-1))
+		}
+		f += c * float64(v) * float64(v)
+	}
+	return T(f)
+}
+
+//Zakharov is sum(x^2)+(sum(.5*i*x))^2+(sum(.5*i*x))^4. Unimodal and non separable.
+func Zakharov[T pso.Float](x []T) T {
+	var s1, s2 float64
+	for i, v := range x {
+		s1 += float64(v) * float64(v)
+		s2 += .5 * float64(i+1) * float64(v)
+	}
+	return T(s1 + s2*s2 + s2*s2*s2*s2)
+}
+
+//Rosenbrock is the banana valley function. Non separable with its minimum at x=1.
+func Rosenbrock[T pso.Float](x []T) T {
+	var f float64
+	for i := 0; i < len(x)-1; i++ {
+		a := float64(x[i+1]) - float64(x[i])*float64(x[i])
+		b := 1 - float64(x[i])
+		f += 100*a*a + b*b
+	}
+	return T(f)
+}
+
+//Rastrigin is 10d+sum(x^2-10cos(2 pi x)). Multimodal and separable.
+func Rastrigin[T pso.Float](x []T) T {
+	f := 10 * float64(len(x))
+	for _, v := range x {
+		f += float64(v)*float64(v) - 10*math.Cos(2*math.Pi*float64(v))
+	}
+	return T(f)
+}
+
+//Ackley is multimodal and non separable with a nearly flat outer region.
+func Ackley[T pso.Float](x []T) T {
+	if len(x) == 0 {
+		return 0
+	}
+	var s1, s2 float64
+	for _, v := range x {
+		s1 += float64(v) * float64(v)
+		s2 += math.Cos(2 * math.Pi * float64(v))
+	}
+	n := float64(len(x))
+	return T(-20*math.Exp(-.2*math.Sqrt(s1/n)) - math.Exp(s2/n) + 20 + math.E)
+}
+
+//Griewank is sum(x^2)/4000-prod(cos(x/sqrt(i)))+1. Multimodal and non separable.
+func Griewank[T pso.Float](x []T) T {
+	s, p := 0.0, 1.0
+	for i, v := range x {
+		s += float64(v) * float64(v) / 4000
+		p *= math.Cos(float64(v) / math.Sqrt(float64(i+1)))
+	}
+	return T(s - p + 1)
+}
+
+//Schwefel is 418.9829d-sum(x sin(sqrt|x|)). Multimodal and separable with the best minimum far from the next best ones.
+//The minimum value is close to but not exactly 0.
+//
+//Outside of [-500,500] x is folded back in and penalized like the CEC 2014 modified Schwefel, so nothing beats the minimum
+//when a transform moves points there.
+func Schwefel[T pso.Float](x []T) T {
+	d := float64(len(x))
+	f := 418.9828872724338 * d
+	for _, v := range x {
+		z := float64(v)
+		switch {
+		case z > 500:
+			w := 500 - math.Mod(z, 500)
+			f -= w*math.Sin(math.Sqrt(w)) - (z-500)*(z-500)/(10000*d)
+		case z < -500:
+			w := math.Mod(-z, 500) - 500
+			f -= w*math.Sin(math.Sqrt(-w)) - (z+500)*(z+500)/(10000*d)
+		default:
+			f -= z * math.Sin(math.Sqrt(math.Abs(z)))
+		}
+	}
+	return T(f)
+}
+
+//Levy is multimodal and non separable with its minimum at x=1.
+func Levy[T pso.Float](x []T) T {
+	if len(x) == 0 {
+		return 0
+	}
+	w := func(v T) float64 { return 1 + (float64(v)-1)/4 }
+	s := math.Sin(math.Pi * w(x[0]))
+	f := s * s
+	for _, v := range x[:len(x)-1] {
+		wi := w(v)
+		s = math.Sin(math.Pi*wi + 1)
+		f += (wi - 1) * (wi - 1) * (1 + 10*s*s)
+	}
+	wd := w(x[len(x)-1])
+	s = math.Sin(2 * math.Pi * wd)
+	f += (wd - 1) * (wd - 1) * (1 + s*s)
+	return T(f)
+}
+
+//StyblinskiTang is .5*sum(x^4-16x^2+5x). Multimodal and separable. The minimum is about -39.166*d.
+func StyblinskiTang[T pso.Float](x []T) T {
+	var f float64
+	for _, v := range x {
+		x2 := float64(v) * float64(v)
+		f += x2*x2 - 16*x2 + 5*float64(v)
+	}
+	return T(f / 2)
+}
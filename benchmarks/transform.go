@@ -0,0 +1,179 @@
+package benchmarks
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/dereklstinson/pso"
+)
+
+//Transform moves, turns and stretches the input of a benchmark function so it is no longer centered on the origin or separable.
+//The wrapped function is evaluated at z = Rotation * (Scale * (x - Shift)).
+//
+//Any of the fields can be nil to leave that part out.
+type Transform struct {
+	Shift    []float64
+	Rotation [][]float64
+	Scale    []float64
+}
+
+//NewTransform makes a Transform with a random shift and a random rotation for f in dims dimensions.  The same seed always gives the same Transform.
+//
+//The shift is picked so the optimum lands inside of the middle 80 percent of the recommended bounds of f.
+//If Scale is set afterwards the optimum moves with it.
+func NewTransform(seed int64, dims int, f Function) Transform {
+	rng := rand.New(rand.NewSource(seed))
+	t := Transform{Rotation: randomrotation(rng, dims)}
+	w := f.Upper - f.Lower
+	t.Shift = randomshift(rng, dims, f.Lower+.1*w, f.Upper-.1*w)
+	optimum := f.Transformed(t).Optimum(dims)
+	for i := range t.Shift {
+		t.Shift[i] -= optimum[i] - t.Shift[i]
+	}
+	return t
+}
+
+//RandomShift returns a shift vector with each value uniform in [lower,upper).
+func RandomShift(seed int64, dims int, lower, upper float64) []float64 {
+	return randomshift(rand.New(rand.NewSource(seed)), dims, lower, upper)
+}
+
+//RandomRotation returns a random orthogonal dims x dims matrix.
+func RandomRotation(seed int64, dims int) [][]float64 {
+	return randomrotation(rand.New(rand.NewSource(seed)), dims)
+}
+
+//RandomScale returns per dimension scales that are log uniform in [min,max).
+func RandomScale(seed int64, dims int, min, max float64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	scale := make([]float64, dims)
+	for i := range scale {
+		scale[i] = math.Exp(math.Log(min) + rng.Float64()*(math.Log(max)-math.Log(min)))
+	}
+	return scale
+}
+
+func randomshift(rng *rand.Rand, dims int, lower, upper float64) []float64 {
+	shift := make([]float64, dims)
+	for i := range shift {
+		shift[i] = lower + rng.Float64()*(upper-lower)
+	}
+	return shift
+}
+
+//randomrotation does Gram-Schmidt on a matrix of normal random numbers.
+func randomrotation(rng *rand.Rand, dims int) [][]float64 {
+	r := make([][]float64, dims)
+	for i := range r {
+		for {
+			r[i] = make([]float64, dims)
+			for j := range r[i] {
+				r[i][j] = rng.NormFloat64()
+			}
+			for k := 0; k < i; k++ {
+				d := dot(r[i], r[k])
+				for j := range r[i] {
+					r[i][j] -= d * r[k][j]
+				}
+			}
+			n := math.Sqrt(dot(r[i], r[i]))
+			if n > 1e-8 {
+				for j := range r[i] {
+					r[i][j] /= n
+				}
+				break
+			}
+		}
+	}
+	return r
+}
+
+func dot(a, b []float64) float64 {
+	var d float64
+	for i := range a {
+		d += a[i] * b[i]
+	}
+	return d
+}
+
+//Apply wraps objective so it is evaluated through the transform.
+func Apply[T pso.Float](t Transform, objective func([]T) T) func([]T) T {
+	return Shifted(t.Shift, Scaled(t.Scale, Rotated(t.Rotation, objective)))
+}
+
+//Shifted wraps objective so it is evaluated at x-shift. A nil shift returns objective.
+func Shifted[T pso.Float](shift []float64, objective func([]T) T) func([]T) T {
+	if shift == nil {
+		return objective
+	}
+	return func(x []T) T {
+		y := make([]T, len(x))
+		for i := range x {
+			y[i] = x[i] - T(shift[i])
+		}
+		return objective(y)
+	}
+}
+
+//Scaled wraps objective so it is evaluated at scale*x. A nil scale returns objective.
+func Scaled[T pso.Float](scale []float64, objective func([]T) T) func([]T) T {
+	if scale == nil {
+		return objective
+	}
+	return func(x []T) T {
+		y := make([]T, len(x))
+		for i := range x {
+			y[i] = x[i] * T(scale[i])
+		}
+		return objective(y)
+	}
+}
+
+//Rotated wraps objective so it is evaluated at rotation*x. A nil rotation returns objective.
+func Rotated[T pso.Float](rotation [][]float64, objective func([]T) T) func([]T) T {
+	if rotation == nil {
+		return objective
+	}
+	return func(x []T) T {
+		y := make([]T, len(x))
+		for i := range rotation {
+			var v float64
+			for j := range x {
+				v += rotation[i][j] * float64(x[j])
+			}
+			y[i] = T(v)
+		}
+		return objective(y)
+	}
+}
+
+//Transformed returns f with t applied to it.  The optimum is moved to where the transform puts it.
+func (f Function) Transformed(t Transform) Function {
+	g := f
+	g.F32 = Apply(t, f.F32)
+	g.F64 = Apply(t, f.F64)
+	if t.Rotation != nil {
+		g.Separable = false
+	}
+	g.optimum = func(dims int) []float64 {
+		z := f.optimum(dims)
+		x := make([]float64, dims)
+		for i := range x {
+			if t.Rotation == nil {
+				x[i] = z[i]
+			} else {
+				for j := range z {
+					x[i] += t.Rotation[j][i] * z[j]
+				}
+			}
+			if t.Scale != nil {
+				x[i] /= t.Scale[i]
+			}
+			if t.Shift != nil {
+				x[i] += t.Shift[i]
+			}
+		}
+		return x
+	}
+	return g
+}
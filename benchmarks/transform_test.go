@@ -0,0 +1,47 @@
+package benchmarks
+
+import (
+	"math/rand"
+	"testing"
+)
+
+//TestNewTransformOptimum checks that NewTransform puts the optimum in the middle 80 percent of the bounds of every function.
+func TestNewTransformOptimum(t *testing.T) {
+	for _, f := range All() {
+		w := f.Upper - f.Lower
+		lo, hi := f.Lower+.1*w, f.Upper-.1*w
+		for seed := int64(0); seed < 20; seed++ {
+			optimum := f.Transformed(NewTransform(seed, 5, f)).Optimum(5)
+			for i, x := range optimum {
+				if x < lo-1e-9 || x > hi+1e-9 {
+					t.Fatalf("%s seed %d: optimum[%d] is %v, want it in [%v,%v]", f.Name, seed, i, x, lo, hi)
+				}
+			}
+		}
+	}
+}
+
+//TestTransformedOptimumValue samples the bounds of every transformed function and checks that nothing beats OptimumValue.
+func TestTransformedOptimumValue(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, f := range All() {
+		for _, dims := range []int{2, 5} {
+			for seed := int64(0); seed < 10; seed++ {
+				g := f.Transformed(NewTransform(seed, dims, f))
+				best := g.OptimumValue(dims)
+				if v := g.F64(g.Optimum(dims)); v > best+1e-6 {
+					t.Fatalf("%s seed %d in %d dims: value at the optimum is %v, want %v", f.Name, seed, dims, v, best)
+				}
+				x := make([]float64, dims)
+				for n := 0; n < 2000; n++ {
+					for i := range x {
+						x[i] = f.Lower + rng.Float64()*(f.Upper-f.Lower)
+					}
+					if v := g.F64(x); v < best-1e-6 {
+						t.Fatalf("%s seed %d in %d dims: %v at %v beats the optimum value %v", f.Name, seed, dims, v, x, best)
+					}
+				}
+			}
+		}
+	}
+}
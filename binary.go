@@ -0,0 +1,114 @@
+package pso
+
+import "math"
+
+//Transfer is the flag for the transfer function a Binary swarm uses to turn a velocity into a probability.
+type Transfer int32
+
+//Sigmoid sets Sigmoid Transfer. This is Kennedy and Eberhart's S-shaped transfer and the default.
+//A bit is set with probability 1/(1+e^-v).
+func (t *Transfer) Sigmoid() Transfer { *t = Transfer(1); return *t }
+
+//VErf sets VErf Transfer.  A bit is flipped with probability |erf(sqrt(pi)/2*v)|.
+func (t *Transfer) VErf() Transfer { *t = Transfer(2); return *t }
+
+//VTanh sets VTanh Transfer.  A bit is flipped with probability |tanh(v)|.
+func (t *Transfer) VTanh() Transfer { *t = Transfer(3); return *t }
+
+//VSqrt sets VSqrt Transfer.  A bit is flipped with probability |v/sqrt(1+v^2)|.
+func (t *Transfer) VSqrt() Transfer { *t = Transfer(4); return *t }
+
+//VArctan sets VArctan Transfer.  A bit is flipped with probability |2/pi*atan(pi/2*v)|.
+func (t *Transfer) VArctan() Transfer { *t = Transfer(5); return *t }
+
+//probability returns the probability for velocity v and if it is a probability of flipping the bit (V-shaped) instead of setting it (S-shaped).
+func (t Transfer) probability(v float64) (p float64, flip bool) {
+	var f Transfer
+	switch t {
+	case f.VErf():
+		return math.Abs(math.Erf(math.Sqrt(math.Pi) / 2 * v)), true
+	case f.VTanh():
+		return math.Abs(math.Tanh(v)), true
+	case f.VSqrt():
+		return math.Abs(v / math.Sqrt(1+v*v)), true
+	case f.VArctan():
+		return math.Abs(2 / math.Pi * math.Atan(math.Pi/2*v)), true
+	}
+	return 1 / (1 + math.Exp(-v)), false
+}
+
+//SetBinary sets the swarm to Binary mode for 0/1 problems like feature selection.
+//
+//Every position value is 0 or 1.  The velocity is updated like ConstantInertia and kept within vmax (4 to 6 is common),
+//then transfer turns it into the probability of the bit being set (Sigmoid) or flipped (the V-shaped transfers).
+//Use Bits to turn a position into a []bool.  Fitnesses are passed with SyncUpdate or AsyncUpdate like any other mode.
+//
+//It returns a *ConfigError and leaves the swarm alone if the values aren't valid.
+func (s *Swarm[T]) SetBinary(
+	numofparticles int,
+	dims int,
+	cognative T,
+	social T,
+	vmax T,
+	inertiamax T,
+	transfer Transfer) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	old := s.transfer
+	s.transfer = transfer
+	var m Mode
+	err := s.setswarm(m.Binary(), numofparticles, dims, cognative, social, vmax, 0, 1, .5, inertiamax)
+	if err != nil {
+		s.transfer = old
+	}
+	return err
+}
+
+//startbits turns the start position of p into bits when the swarm is in Binary mode.
+func (s *Swarm[T]) startbits(p *particle[T]) {
+	var m Mode
+	if s.mode != m.Binary() {
+		return
+	}
+	for i := range p.position {
+		if p.position[i] < .5 {
+			p.position[i] = 0
+		} else {
+			p.position[i] = 1
+		}
+		p.indvbest[i] = p.position[i]
+	}
+}
+
+//BinaryUpdater is the VelocityUpdater used by Binary mode.
+type BinaryUpdater[T Float] struct {
+	Transfer Transfer
+}
+
+//Update does the binary update.
+func (u BinaryUpdater[T]) Update(p *ParticleState[T]) {
+	for i := range p.Velocity {
+		p.Velocity[i] = (p.Inertia * p.Velocity[i]) + (p.Cognative * randf[T](p.Rng) * (p.PersonalBest[i] - p.Position[i])) + (p.Social * randf[T](p.Rng) * (p.InformantBest[i] - p.Position[i]))
+		p.Velocity[i] = minmagnitude(p.Velocity[i], p.Vmax)
+
+		prob, flip := u.Transfer.probability(float64(p.Velocity[i]))
+		r := float64(randf[T](p.Rng))
+		switch {
+		case flip && r < prob:
+			p.Position[i] = 1 - p.Position[i]
+		case !flip && r < prob:
+			p.Position[i] = 1
+		case !flip:
+			p.Position[i] = 0
+		}
+	}
+}
+
+//Bits returns position as bits.  Values of .5 or more are true.
+func Bits[T Float](position []T) []bool {
+	bits := make([]bool, len(position))
+	for i := range position {
+		bits[i] = position[i] >= .5
+	}
+	return bits
+}
@@ -0,0 +1,61 @@
+package pso
+
+import "testing"
+
+//TestBinaryPositions checks that every transfer keeps the positions of a Binary swarm at 0 or 1.
+func TestBinaryPositions(t *testing.T) {
+	var f Transfer
+	for _, transfer := range []Transfer{f.Sigmoid(), f.VErf(), f.VTanh(), f.VSqrt(), f.VArctan()} {
+		s := CreateSwarm64(1)
+		err := s.SetBinary(10, 8, 2, 2, 4, .9, transfer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ones := func(x []float64) float64 {
+			var sum float64
+			for _, b := range Bits(x) {
+				if b {
+					sum++
+				}
+			}
+			return sum
+		}
+		_, err = s.Minimize(ones, Budget64{MaxIterations: 30})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range s.particles {
+			for _, v := range s.particles[i].position {
+				if v != 0 && v != 1 {
+					t.Fatalf("transfer %d: position %v isn't bits", transfer, s.particles[i].position)
+				}
+			}
+		}
+	}
+}
+
+//TestChangeModeBinary checks that a continuous swarm can't be changed to Binary mode with ChangeMode.
+func TestChangeModeBinary(t *testing.T) {
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(4, 3, 1.49445, 1.49445, 1, -5, 5, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m Mode
+	err = s.ChangeMode(m.Binary())
+	if _, ok := err.(*ConfigError); !ok {
+		t.Fatalf("ChangeMode(Binary) returned %v, want a *ConfigError", err)
+	}
+	if mode := s.Config().Mode; mode != m.ConstantInertia() {
+		t.Fatalf("mode is %v, want ConstantInertia", mode)
+	}
+	var f Transfer
+	err = s.SetBinary(4, 3, 2, 2, 4, .9, f.Sigmoid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.ChangeMode(m.Binary())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,173 @@
+package pso
+
+import (
+	"fmt"
+	"math"
+)
+
+//Boundary is the flag for how a particle is handled when it flies out of the swarm bounds.
+//
+//If bounds are set and no Boundary was set then Clamp is used.
+type Boundary int32
+
+//Clamp sets Clamp Boundary. The particle is put on the bound it crossed and its velocity in that dimension is set to zero (absorbing).
+func (b *Boundary) Clamp() Boundary { *b = Boundary(1); return *b }
+
+//Reflect sets Reflect Boundary. The particle bounces off the bound it crossed and its velocity in that dimension is reversed.
+func (b *Boundary) Reflect() Boundary { *b = Boundary(2); return *b }
+
+//Random sets Random Boundary. The particle is given a random position inside the bounds for that dimension.
+func (b *Boundary) Random() Boundary { *b = Boundary(3); return *b }
+
+//Periodic sets Periodic Boundary. The particle wraps around to the other side of the bounds.
+func (b *Boundary) Periodic() Boundary { *b = Boundary(4); return *b }
+
+//Damping sets Damping Boundary. Like Reflect, but the velocity is reversed and damped by a random factor in [0,1).
+func (b *Boundary) Damping() Boundary { *b = Boundary(5); return *b }
+
+//SetBounds sets the per dimension search bounds of the swarm.  Bounds are enforced inside of every particle update
+//using the Boundary that was set with SetBoundary.
+//
+//lower and upper need to be the same length as the dims of the swarm and lower[i] < upper[i].
+//Passing nil for both will remove the bounds.
+//
+//Particles already in the swarm are clamped inside of the new bounds. New and resetted particles start inside
+//of both the start values and the bounds.
+func (s *Swarm[T]) SetBounds(lower, upper []T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.setbounds(lower, upper)
+}
+
+func (s *Swarm[T]) setbounds(lower, upper []T) error {
+	if lower == nil && upper == nil {
+		s.lower, s.upper = nil, nil
+		return nil
+	}
+	if len(lower) != len(s.globalposition) || len(upper) != len(s.globalposition) {
+		return &ConfigError{"Lower", fmt.Sprintf("and Upper need %d values, got %d and %d", len(s.globalposition), len(lower), len(upper))}
+	}
+	for i := range lower {
+		if !(lower[i] < upper[i]) {
+			return &ConfigError{fmt.Sprintf("Lower[%d]", i), fmt.Sprintf("needs to be < Upper[%d], got %v and %v", i, lower[i], upper[i])}
+		}
+	}
+	s.lower = make([]T, len(lower))
+	s.upper = make([]T, len(upper))
+	copy(s.lower, lower)
+	copy(s.upper, upper)
+	for i := range s.particles {
+		p := &s.particles[i]
+		for j := range p.position {
+			p.position[j] = clamp(p.position[j], s.lower[j], s.upper[j])
+			p.indvbest[j] = clamp(p.indvbest[j], s.lower[j], s.upper[j])
+		}
+	}
+	return nil
+}
+
+//SetBoundary sets how particles are handled when they fly out of the bounds set with SetBounds.
+func (s *Swarm[T]) SetBoundary(b Boundary) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.boundary = b
+}
+
+//Bounds returns copies of the lower and upper bounds of the swarm.  They are nil if no bounds are set.
+func (s *Swarm[T]) Bounds() (lower, upper []T) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if s.lower == nil {
+		return nil, nil
+	}
+	lower = make([]T, len(s.lower))
+	upper = make([]T, len(s.upper))
+	copy(lower, s.lower)
+	copy(upper, s.upper)
+	return lower, upper
+}
+
+//startrange returns the range new and resetted particles start in. It is the start values inside of the bounds.
+func (s *Swarm[T]) startrange() (min, max []T) {
+	if s.lower == nil {
+		return s.xminstart, s.xmaxstart
+	}
+	min = make([]T, len(s.xminstart))
+	max = make([]T, len(s.xmaxstart))
+	for i := range min {
+		min[i] = clamp(s.xminstart[i], s.lower[i], s.upper[i])
+		max[i] = clamp(s.xmaxstart[i], s.lower[i], s.upper[i])
+		if !(min[i] < max[i]) {
+			min[i], max[i] = s.lower[i], s.upper[i]
+		}
+	}
+	return min, max
+}
+
+//bound puts the particle back inside of lower and upper.
+func (p *particle[T]) bound(lower, upper []T, b Boundary) {
+	if lower == nil || upper == nil {
+		return
+	}
+	var f Boundary
+	for i, x := range p.position {
+		lo, hi := lower[i], upper[i]
+		if x >= lo && x <= hi {
+			continue
+		}
+		switch b {
+		case f.Reflect():
+			p.position[i] = mirror(x, lo, hi)
+			p.velocity[i] = -p.velocity[i]
+		case f.Random():
+			p.position[i] = lo + (hi-lo)*randf[T](p.rng)
+		case f.Periodic():
+			p.position[i] = wrap(x, lo, hi)
+		case f.Damping():
+			r := randf[T](p.rng)
+			if x < lo {
+				p.position[i] = lo + r*(lo-x)
+			} else {
+				p.position[i] = hi - r*(x-hi)
+			}
+			p.position[i] = clamp(p.position[i], lo, hi)
+			p.velocity[i] = -r * p.velocity[i]
+		default:
+			p.position[i] = clamp(x, lo, hi)
+			p.velocity[i] = 0
+		}
+	}
+}
+
+func clamp[T Float](x, lo, hi T) T {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
+
+//mirror folds x back into [lo,hi] as many times as it takes.
+func mirror[T Float](x, lo, hi T) T {
+	w := float64(hi - lo)
+	d := math.Mod(float64(x-lo), 2*w)
+	if d < 0 {
+		d += 2 * w
+	}
+	if d > w {
+		d = 2*w - d
+	}
+	return clamp(lo+T(d), lo, hi)
+}
+
+//wrap maps x into [lo,hi] periodically.
+func wrap[T Float](x, lo, hi T) T {
+	w := float64(hi - lo)
+	d := math.Mod(float64(x-lo), w)
+	if d < 0 {
+		d += w
+	}
+	return clamp(lo+T(d), lo, hi)
+}
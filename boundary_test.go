@@ -0,0 +1,80 @@
+package pso
+
+import "testing"
+
+//TestBoundaries checks that every Boundary keeps the particles inside of the bounds while the objective pulls them out.
+func TestBoundaries(t *testing.T) {
+	var b Boundary
+	lower, upper := []float64{-1, -1, -1}, []float64{1, 1, 1}
+	outside := func(x []float64) float64 {
+		var sum float64
+		for _, v := range x {
+			sum += (v - 5) * (v - 5)
+		}
+		return sum
+	}
+	for _, boundary := range []Boundary{b.Clamp(), b.Reflect(), b.Random(), b.Periodic(), b.Damping()} {
+		s := CreateSwarm64(1)
+		err := s.SetConstantInertia(10, 3, 1.49445, 1.49445, 10, -1, 1, .9)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetBoundary(boundary)
+		err = s.SetBounds(lower, upper)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k := 0; k < 30; k++ {
+			_, err = s.Minimize(outside, Budget64{MaxIterations: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, p := range s.particles {
+				x := p.position
+				for j, v := range x {
+					if v < lower[j] || v > upper[j] {
+						t.Fatalf("boundary %d: particle %d is at %v", boundary, i, x)
+					}
+				}
+			}
+		}
+		if boundary == b.Clamp() {
+			for j, v := range s.GlobalPosition() {
+				if v != upper[j] {
+					t.Fatalf("clamped swarm's best is %v, want it on the upper bound", s.GlobalPosition())
+				}
+			}
+		}
+	}
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(10, 3, 1.49445, 1.49445, 10, -1, 1, .9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.SetBounds(upper, lower); err == nil {
+		t.Fatal("SetBounds with lower > upper returned no error")
+	}
+	if err = s.SetBounds(lower[:2], upper[:2]); err == nil {
+		t.Fatal("SetBounds with 2 of 3 dims returned no error")
+	}
+}
+
+//TestMirrorWrap checks the folding Reflect and Periodic use.
+func TestMirrorWrap(t *testing.T) {
+	for _, c := range []struct {
+		f       func(x, lo, hi float64) float64
+		name    string
+		x, want float64
+	}{
+		{mirror[float64], "mirror", 1.5, .5},
+		{mirror[float64], "mirror", -1.25, -.75},
+		{mirror[float64], "mirror", 3.5, -.5},
+		{wrap[float64], "wrap", 1.5, -.5},
+		{wrap[float64], "wrap", -1.5, .5},
+		{wrap[float64], "wrap", 4.25, .25},
+	} {
+		if got := c.f(c.x, -1, 1); got != c.want {
+			t.Fatalf("%s(%v, -1, 1) is %v, want %v", c.name, c.x, got, c.want)
+		}
+	}
+}
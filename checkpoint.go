@@ -0,0 +1,364 @@
+package pso
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+//checkpointversion is the version of the checkpoint format.  It goes up when the snapshot changes.
+const checkpointversion = 1
+
+var checkpointmagic = [4]byte{'P', 'S', 'O', 'C'}
+
+//snapshot is everything needed to restart a swarm where it left off.
+//Floats are stored as numbers so they are the same at both precisions and NaN and Inf survive JSON.
+type snapshot struct {
+	Version        int
+	Precision      int
+	K              int
+	Max            bool
+	HasBest        bool
+	Fitness        number
+	Violation      number
+	Keys           []number
+	Cognative      number
+	Social         number
+	Vmax           number
+	Constriction   number
+	AlphaMax       number
+	InertiaMax     number
+	MinStart       []number
+	MaxStart       []number
+	Lower          []number
+	Upper          []number
+	Boundary       Boundary
+	Mode           Mode
+	Transfer       Transfer
+	Failure        Failure
+	Retries        int
+	Failures       Failures
+	GlobalPosition []number
+	Rng            []byte
+	Topology       []byte `json:",omitempty"`
+	Constraint     []byte `json:",omitempty"`
+	Particles      []particlesnapshot
+}
+
+type particlesnapshot struct {
+	HasBest   bool
+	Position  []number
+	Velocity  []number
+	Best      []number
+	Fitness   number
+	Violation number
+	Keys      []number
+	Retries   int
+	Inertia   number
+	Alpha     number
+	Rng       []byte
+}
+
+//number is a float64 that writes NaN and Inf to JSON as strings.
+type number float64
+
+func (n number) MarshalJSON() ([]byte, error) {
+	f := float64(n)
+	switch {
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Inf"`), nil
+	}
+	return json.Marshal(f)
+}
+
+func (n *number) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"NaN"`:
+		*n = number(math.NaN())
+	case `"+Inf"`:
+		*n = number(math.Inf(1))
+	case `"-Inf"`:
+		*n = number(math.Inf(-1))
+	default:
+		var f float64
+		err := json.Unmarshal(data, &f)
+		if err != nil {
+			return err
+		}
+		*n = number(f)
+	}
+	return nil
+}
+
+func tonumbers[T Float](x []T) []number {
+	if x == nil {
+		return nil
+	}
+	n := make([]number, len(x))
+	for i := range x {
+		n[i] = number(x[i])
+	}
+	return n
+}
+
+func fromnumbers[T Float](n []number) []T {
+	if n == nil {
+		return nil
+	}
+	x := make([]T, len(n))
+	for i := range n {
+		x[i] = T(n[i])
+	}
+	return x
+}
+
+//Save writes a checkpoint of the swarm to w in a versioned binary format.  Use Load to restart from it.
+//
+//Everything that changes during a run is saved, including the rng states, so a loaded swarm continues
+//exactly like the swarm that was saved.  VelocityUpdaters passed with SetUpdater and Comparators are not saved, and a Topology or
+//ConstraintHandler is only saved if it implements encoding.BinaryMarshaler (the built in ones that have state do).
+func (s *Swarm[T]) Save(w io.Writer) error {
+	snap, err := s.snapshot()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(checkpointmagic[:])
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte{checkpointversion, byte(snap.Precision)})
+	if err != nil {
+		return err
+	}
+	return gob.NewEncoder(w).Encode(snap)
+}
+
+//Load restarts the swarm from a checkpoint written with Save.
+//
+//The swarm needs to be the same precision as the one that was saved. If the saved swarm was in Custom mode
+//SetUpdater needs to be called with the same VelocityUpdater, and SetTopology and SetConstraintHandler with the same kind of
+//Topology and ConstraintHandler, before Load.
+func (s *Swarm[T]) Load(r io.Reader) error {
+	var header [6]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(header[:4], checkpointmagic[:]) {
+		return errors.New("Not a swarm checkpoint")
+	}
+	if header[4] != checkpointversion {
+		return errors.New("Unsupported checkpoint version")
+	}
+	if int(header[5]) != precision[T]() {
+		return errors.New("Checkpoint precision not the same as swarm")
+	}
+	var snap snapshot
+	err = gob.NewDecoder(r).Decode(&snap)
+	if err != nil {
+		return err
+	}
+	return s.restore(&snap)
+}
+
+//SaveJSON writes a checkpoint of the swarm to w as human readable JSON.  See Save.
+//
+//The rng states are stored as base64 strings.
+func (s *Swarm[T]) SaveJSON(w io.Writer) error {
+	snap, err := s.snapshot()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(snap)
+}
+
+//LoadJSON restarts the swarm from a checkpoint written with SaveJSON. See Load.
+func (s *Swarm[T]) LoadJSON(r io.Reader) error {
+	var snap snapshot
+	err := json.NewDecoder(r).Decode(&snap)
+	if err != nil {
+		return err
+	}
+	if snap.Version != checkpointversion {
+		return errors.New("Unsupported checkpoint version")
+	}
+	if snap.Precision != precision[T]() {
+		return errors.New("Checkpoint precision not the same as swarm")
+	}
+	return s.restore(&snap)
+}
+
+func (s *Swarm[T]) snapshot() (*snapshot, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	var err error
+	snap := &snapshot{
+		Version:        checkpointversion,
+		Precision:      precision[T](),
+		K:              s.k,
+		Max:            s.max,
+		HasBest:        s.hasbest,
+		Fitness:        number(s.fitness),
+		Violation:      number(s.violation),
+		Keys:           tonumbers(s.keys),
+		Cognative:      number(s.cognative),
+		Social:         number(s.social),
+		Vmax:           number(s.vmax),
+		Constriction:   number(s.constriction),
+		AlphaMax:       number(s.alphamax),
+		InertiaMax:     number(s.inertiamax),
+		MinStart:       tonumbers(s.xminstart),
+		MaxStart:       tonumbers(s.xmaxstart),
+		Lower:          tonumbers(s.lower),
+		Upper:          tonumbers(s.upper),
+		Boundary:       s.boundary,
+		Mode:           s.mode,
+		Transfer:       s.transfer,
+		Failure:        s.failure,
+		Retries:        s.retries,
+		Failures:       s.failures,
+		GlobalPosition: tonumbers(s.globalposition),
+		Particles:      make([]particlesnapshot, len(s.particles)),
+	}
+	snap.Rng, err = s.source.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := s.topology.(encoding.BinaryMarshaler); ok {
+		snap.Topology, err = m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if m, ok := s.constraint.(encoding.BinaryMarshaler); ok {
+		snap.Constraint, err = m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range s.particles {
+		p := &s.particles[i]
+		p.mux.Lock()
+		snap.Particles[i] = particlesnapshot{
+			HasBest:   p.hasbest,
+			Position:  tonumbers(p.position),
+			Velocity:  tonumbers(p.velocity),
+			Best:      tonumbers(p.indvbest),
+			Fitness:   number(p.fitness),
+			Violation: number(p.violation),
+			Keys:      tonumbers(p.keys),
+			Retries:   p.retries,
+			Inertia:   number(p.inertia),
+			Alpha:     number(p.alpha),
+		}
+		snap.Particles[i].Rng, err = p.source.MarshalBinary()
+		p.mux.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snap, nil
+}
+
+func (s *Swarm[T]) restore(snap *snapshot) error {
+	dims := len(snap.GlobalPosition)
+	if len(snap.MinStart) != dims || len(snap.MaxStart) != dims {
+		return errors.New("Checkpoint start values not the same length as dims")
+	}
+	if (snap.Lower != nil || snap.Upper != nil) && (len(snap.Lower) != dims || len(snap.Upper) != dims) {
+		return errors.New("Checkpoint bounds not the same length as dims")
+	}
+	src := newsource(0)
+	err := src.UnmarshalBinary(snap.Rng)
+	if err != nil {
+		return err
+	}
+	particles := make([]particle[T], len(snap.Particles))
+	for i, ps := range snap.Particles {
+		if len(ps.Position) != dims || len(ps.Velocity) != dims || len(ps.Best) != dims {
+			return errors.New("Checkpoint particle not the same length as dims")
+		}
+		psrc := newsource(0)
+		err = psrc.UnmarshalBinary(ps.Rng)
+		if err != nil {
+			return err
+		}
+		particles[i] = particle[T]{
+			mux:       new(sync.Mutex),
+			hasbest:   ps.HasBest,
+			rng:       rand.New(psrc),
+			source:    psrc,
+			fitness:   T(ps.Fitness),
+			violation: T(ps.Violation),
+			keys:      fromnumbers[T](ps.Keys),
+			retries:   ps.Retries,
+			position:  fromnumbers[T](ps.Position),
+			indvbest:  fromnumbers[T](ps.Best),
+			velocity:  fromnumbers[T](ps.Velocity),
+			inertia:   T(ps.Inertia),
+			alpha:     T(ps.Alpha),
+		}
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if u, ok := s.topology.(encoding.BinaryUnmarshaler); ok && snap.Topology != nil {
+		err = u.UnmarshalBinary(snap.Topology)
+		if err != nil {
+			return err
+		}
+	}
+	if u, ok := s.constraint.(encoding.BinaryUnmarshaler); ok && snap.Constraint != nil {
+		err = u.UnmarshalBinary(snap.Constraint)
+		if err != nil {
+			return err
+		}
+	}
+	s.k = snap.K
+	s.max = snap.Max
+	s.hasbest = snap.HasBest
+	s.fitness = T(snap.Fitness)
+	s.violation = T(snap.Violation)
+	s.keys = fromnumbers[T](snap.Keys)
+	s.cognative = T(snap.Cognative)
+	s.social = T(snap.Social)
+	s.vmax = T(snap.Vmax)
+	s.constriction = T(snap.Constriction)
+	s.alphamax = T(snap.AlphaMax)
+	s.inertiamax = T(snap.InertiaMax)
+	s.xminstart = fromnumbers[T](snap.MinStart)
+	s.xmaxstart = fromnumbers[T](snap.MaxStart)
+	s.lower = fromnumbers[T](snap.Lower)
+	s.upper = fromnumbers[T](snap.Upper)
+	s.boundary = snap.Boundary
+	s.transfer = snap.Transfer
+	s.failure = snap.Failure
+	s.retries = snap.Retries
+	s.failures = snap.Failures
+	s.setmode(snap.Mode)
+	s.globalposition = fromnumbers[T](snap.GlobalPosition)
+	s.source = src
+	s.rng = rand.New(src)
+	s.particles = particles
+	return nil
+}
+
+//precision returns the number of bits in T.
+func precision[T Float]() int {
+	var t T
+	if _, ok := any(t).(float32); ok {
+		return 32
+	}
+	return 64
+}
@@ -0,0 +1,120 @@
+package pso
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+//resumecase sets up a swarm for the resume tests.
+type resumecase[T Float] struct {
+	name  string
+	setup func(s *Swarm[T]) error
+}
+
+//resumecases returns the swarms checked by the resume tests.
+func resumecases[T Float]() []resumecase[T] {
+	var m Mode
+	var b Boundary
+	var f Transfer
+	return []resumecase[T]{
+		{"RandomInformants", func(s *Swarm[T]) error {
+			s.SetTopology(RandomInformants(3))
+			return s.GenericSet(m.ConstantInertia(), 12, 3, 1.49445, 1.49445, 1, -5, 5, .5, .7)
+		}},
+		{"RandomBoundary", func(s *Swarm[T]) error {
+			err := s.GenericSet(m.InertiaReduction(), 12, 3, 1.49445, 1.49445, 2, -5, 5, .99, .9)
+			if err != nil {
+				return err
+			}
+			s.SetBoundary(b.Random())
+			return s.SetBounds([]T{-1, -2, -3}, []T{1, 2, 3})
+		}},
+		{"Binary", func(s *Swarm[T]) error {
+			return s.SetBinary(12, 8, 2, 2, 4, .9, f.VTanh())
+		}},
+	}
+}
+
+//TestResume checks that a swarm loaded from a checkpoint ends exactly like one that wasn't stopped,
+//with both formats and at both precisions.
+func TestResume(t *testing.T) {
+	t.Run("float32", testresume[float32])
+	t.Run("float64", testresume[float64])
+}
+
+func testresume[T Float](t *testing.T) {
+	formats := []struct {
+		name string
+		save func(s *Swarm[T], w io.Writer) error
+		load func(s *Swarm[T], r io.Reader) error
+	}{
+		{"Save", (*Swarm[T]).Save, (*Swarm[T]).Load},
+		{"SaveJSON", (*Swarm[T]).SaveJSON, (*Swarm[T]).LoadJSON},
+	}
+	for _, c := range resumecases[T]() {
+		for _, format := range formats {
+			t.Run(c.name+"/"+format.name, func(t *testing.T) {
+				newswarm := func(seed int) *Swarm[T] {
+					s := CreateSwarm[T](seed)
+					err := c.setup(s)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return s
+				}
+				whole := newswarm(1)
+				resumerun(t, whole, 30)
+				saved := newswarm(1)
+				resumerun(t, saved, 15)
+				var buf bytes.Buffer
+				err := format.save(saved, &buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				loaded := newswarm(2)
+				err = format.load(loaded, &buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resumerun(t, loaded, 15)
+				sameswarm(t, loaded, whole)
+			})
+		}
+	}
+}
+
+//resumerun does iterations sync updates of s on a shifted sphere.
+func resumerun[T Float](t *testing.T, s *Swarm[T], iterations int) {
+	for k := 0; k < iterations; k++ {
+		fitnesses := make([]T, len(s.particles))
+		for i := range s.particles {
+			for _, v := range s.particles[i].position {
+				fitnesses[i] += (v - .3) * (v - .3)
+			}
+		}
+		err := s.SyncUpdate(fitnesses)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+//sameswarm fails t if got doesn't have the same global best and particle positions as want.
+func sameswarm[T Float](t *testing.T, got, want *Swarm[T]) {
+	t.Helper()
+	if g, w := got.GlobalFitness(), want.GlobalFitness(); g != w {
+		t.Fatalf("global fitness is %v, want %v", g, w)
+	}
+	if g, w := got.GlobalPosition(), want.GlobalPosition(); !equal(g, w) {
+		t.Fatalf("global position is %v, want %v", g, w)
+	}
+	if len(got.particles) != len(want.particles) {
+		t.Fatalf("%d particles, want %d", len(got.particles), len(want.particles))
+	}
+	for i := range want.particles {
+		if g, w := got.particles[i].position, want.particles[i].position; !equal(g, w) {
+			t.Fatalf("particle %d is at %v, want %v", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Command psobench runs every swarm Mode on the benchmark functions and reports how well each one did.
+
+Each Mode, function and dimension combination is run once for every seed.  A run stops when it reaches the target
+error or runs out of iterations.  The final error is the best fitness found minus the known optimum value.
+
+Usage:
+
+	psobench -funcs Sphere,Rastrigin -dims 10,30 -seeds 25 -format csv > results.csv
+
+The output is a table of mean, median and standard deviation of the final error, the success rate at the target,
+and the mean evaluations it took the successful runs to reach the target.  -format picks text, csv or json.
+*/
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dereklstinson/pso"
+	"github.com/dereklstinson/pso/benchmarks"
+)
+
+//row is the summary of all the seeds of one mode, function and dims.
+type row struct {
+	Mode          string  `json:"mode"`
+	Function      string  `json:"function"`
+	Dims          int     `json:"dims"`
+	Runs          int     `json:"runs"`
+	Mean          float64 `json:"mean"`
+	Median        float64 `json:"median"`
+	Std           float64 `json:"std"`
+	SuccessRate   float64 `json:"success_rate"`
+	EvalsToTarget float64 `json:"evals_to_target,omitempty"`
+}
+
+func main() {
+	funcs := flag.String("funcs", "", "comma separated benchmark functions (default all)")
+	modes := flag.String("modes", "", "comma separated modes (default all)")
+	dims := flag.String("dims", "10", "comma separated dimensions")
+	seeds := flag.Int("seeds", 10, "number of independent runs for each combination")
+	seed := flag.Int("seed", 0, "first seed")
+	particles := flag.Int("particles", 40, "particles in each swarm")
+	iters := flag.Int("iters", 1000, "max iterations for each run")
+	target := flag.Float64("target", 1e-8, "error that counts as a success")
+	transform := flag.Bool("transform", false, "shift and rotate each function with a seeded transform")
+	format := flag.String("format", "text", "output format: text, csv or json")
+	flag.Parse()
+
+	fs, err := selectfuncs(*funcs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ms, err := selectmodes(*modes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ds, err := parseints(*dims)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var rows []row
+	for _, m := range ms {
+		for _, f := range fs {
+			for _, d := range ds {
+				errs := make([]float64, 0, *seeds)
+				var evals []float64
+				for i := 0; i < *seeds; i++ {
+					fn := f
+					if *transform {
+						fn = f.Transformed(benchmarks.NewTransform(int64(*seed+i), d, f))
+					}
+					r, err := run(m, fn, d, *particles, *iters, *target, *seed+i)
+					if err != nil {
+						log.Fatal(err)
+					}
+					e := r.Fitness - fn.OptimumValue(d)
+					errs = append(errs, e)
+					if e <= *target {
+						evals = append(evals, float64(r.Evaluations))
+					}
+				}
+				rows = append(rows, summarize(m.String(), f.Name, d, errs, evals))
+			}
+		}
+	}
+	switch *format {
+	case "text":
+		err = writetext(os.Stdout, rows)
+	case "csv":
+		err = writecsv(os.Stdout, rows)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		err = enc.Encode(rows)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+//run sets up a swarm for mode with the usual coefficients for it and minimizes f.
+func run(mode pso.Mode, f benchmarks.Function, dims, particles, iters int, target float64, seed int) (pso.Result64, error) {
+	s := pso.CreateSwarm64(seed)
+	width := f.Upper - f.Lower
+	var m pso.Mode
+	var err error
+	switch mode {
+	case m.Vanilla():
+		err = s.SetVanilla(particles, dims, 2, 2, .2*width, f.Lower, f.Upper)
+	case m.ConstantInertia():
+		err = s.SetConstantInertia(particles, dims, 1.49445, 1.49445, .2*width, f.Lower, f.Upper, .9)
+	case m.InertiaReduction():
+		err = s.SetLinearInertiaReduce(particles, dims, 1.49445, 1.49445, .2*width, f.Lower, f.Upper, 1, .9)
+	case m.Constriction():
+		err = s.SetConstriction(particles, dims, 2.05, 2.05, width, f.Lower, f.Upper)
+	case m.DynamicInertiaMaxVelReduction():
+		err = s.SetDynamicInertiaMaxVelocityReduction(particles, dims, 1.49445, 1.49445, .2, f.Lower, f.Upper, .9)
+	}
+	if err != nil {
+		return pso.Result64{}, err
+	}
+	lower, upper := f.Bounds(dims)
+	err = s.SetBounds(lower, upper)
+	if err != nil {
+		return pso.Result64{}, err
+	}
+	return s.Minimize(f.F64, pso.Budget64{
+		MaxIterations: iters,
+		Target:        f.OptimumValue(dims) + target,
+		UseTarget:     true,
+	})
+}
+
+func summarize(mode, function string, dims int, errs, evals []float64) row {
+	r := row{Mode: mode, Function: function, Dims: dims, Runs: len(errs)}
+	if len(errs) == 0 {
+		return r
+	}
+	sorted := append([]float64(nil), errs...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		r.Median = sorted[n/2]
+	} else {
+		r.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	for _, e := range errs {
+		r.Mean += e
+	}
+	r.Mean /= float64(n)
+	for _, e := range errs {
+		r.Std += (e - r.Mean) * (e - r.Mean)
+	}
+	r.Std = math.Sqrt(r.Std / float64(n))
+	r.SuccessRate = float64(len(evals)) / float64(n)
+	for _, e := range evals {
+		r.EvalsToTarget += e / float64(len(evals))
+	}
+	return r
+}
+
+var header = []string{"mode", "function", "dims", "runs", "mean", "median", "std", "success_rate", "evals_to_target"}
+
+func (r row) fields() []string {
+	evals := ""
+	if r.SuccessRate > 0 {
+		evals = strconv.FormatFloat(r.EvalsToTarget, 'f', 0, 64)
+	}
+	return []string{
+		r.Mode,
+		r.Function,
+		strconv.Itoa(r.Dims),
+		strconv.Itoa(r.Runs),
+		strconv.FormatFloat(r.Mean, 'g', 6, 64),
+		strconv.FormatFloat(r.Median, 'g', 6, 64),
+		strconv.FormatFloat(r.Std, 'g', 6, 64),
+		strconv.FormatFloat(r.SuccessRate, 'f', 2, 64),
+		evals,
+	}
+}
+
+func writetext(w io.Writer, rows []row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, r := range rows {
+		fmt.Fprintln(tw, strings.Join(r.fields(), "\t"))
+	}
+	return tw.Flush()
+}
+
+func writecsv(w io.Writer, rows []row) error {
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	for _, r := range rows {
+		cw.Write(r.fields())
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func selectfuncs(names string) ([]benchmarks.Function, error) {
+	if names == "" {
+		return benchmarks.All(), nil
+	}
+	var fs []benchmarks.Function
+	for _, name := range strings.Split(names, ",") {
+		f, ok := benchmarks.Lookup(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q", name)
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+func selectmodes(names string) ([]pso.Mode, error) {
+	var m pso.Mode
+	all := []pso.Mode{m.Vanilla(), m.ConstantInertia(), m.InertiaReduction(), m.Constriction(), m.DynamicInertiaMaxVelReduction()}
+	if names == "" {
+		return all, nil
+	}
+	var ms []pso.Mode
+	for _, name := range strings.Split(names, ",") {
+		found := false
+		for _, mode := range all {
+			if strings.EqualFold(mode.String(), strings.TrimSpace(name)) {
+				ms = append(ms, mode)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown mode %q", name)
+		}
+	}
+	return ms, nil
+}
+
+func parseints(list string) ([]int, error) {
+	var ints []int
+	for _, s := range strings.Split(list, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		if i < 1 {
+			return nil, fmt.Errorf("dims needs to be positive: %d", i)
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
@@ -0,0 +1,80 @@
+package pso
+
+//Comparator decides which of two records is better.  It is used for the personal bests, the informant bests and the global best.
+//
+//A ConstraintHandler set with SetConstraintHandler is used before a Comparator.  The Comparator only decides between records
+//the ConstraintHandler finds equal.
+type Comparator[T Float] interface {
+	//Better returns true if a is better than b.  max is true if the swarm is maximizing.
+	//Records that are equal should return false so the older best is kept.
+	Better(a, b Record[T], max bool) bool
+}
+
+//ComparatorFunc lets a func be used as a Comparator.
+type ComparatorFunc[T Float] func(a, b Record[T], max bool) bool
+
+//Better calls f(a, b, max).
+func (f ComparatorFunc[T]) Better(a, b Record[T], max bool) bool { return f(a, b, max) }
+
+//SetComparator sets how the swarm orders records.
+//If it is nil (default) only fitness is compared.
+func (s *Swarm[T]) SetComparator(c Comparator[T]) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.comparator = c
+}
+
+type tolerance[T Float] struct {
+	epsilon T
+}
+
+//Tolerance returns a Comparator where a record is only better if its fitness is better by more than epsilon.
+//Fitnesses within epsilon are treated as equal so the older best is kept.
+func Tolerance[T Float](epsilon T) Comparator[T] {
+	return tolerance[T]{epsilon: epsilon}
+}
+
+func (t tolerance[T]) Better(a, b Record[T], max bool) bool {
+	return fitter(a.Fitness, b.Fitness, t.epsilon, max)
+}
+
+type lexicographic[T Float] struct {
+	epsilon T
+	keymax  []bool
+}
+
+//Lexicographic returns a Comparator that compares fitness first and then Keys in order.
+//
+//Fitnesses within epsilon are treated as equal and the tie is broken by the first key that is different.
+//Keys are minimized unless keymax is true for that key.  A key missing from either record ends the comparison,
+//and records that tie on everything keep the older best.
+func Lexicographic[T Float](epsilon T, keymax ...bool) Comparator[T] {
+	return lexicographic[T]{epsilon: epsilon, keymax: append([]bool(nil), keymax...)}
+}
+
+func (l lexicographic[T]) Better(a, b Record[T], max bool) bool {
+	if fitter(a.Fitness, b.Fitness, l.epsilon, max) {
+		return true
+	}
+	if fitter(b.Fitness, a.Fitness, l.epsilon, max) {
+		return false
+	}
+	for i := 0; i < len(a.Keys) && i < len(b.Keys); i++ {
+		if a.Keys[i] == b.Keys[i] {
+			continue
+		}
+		if i < len(l.keymax) && l.keymax[i] {
+			return a.Keys[i] > b.Keys[i]
+		}
+		return a.Keys[i] < b.Keys[i]
+	}
+	return false
+}
+
+//fitter returns true if fitness a is better than b by more than epsilon.
+func fitter[T Float](a, b, epsilon T, max bool) bool {
+	if max {
+		return a-b > epsilon
+	}
+	return b-a > epsilon
+}
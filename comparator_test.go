@@ -0,0 +1,28 @@
+package pso
+
+import "testing"
+
+//TestComparatorBreaksConstraintTies checks that a Comparator decides between records a ConstraintHandler finds equal.
+func TestComparatorBreaksConstraintTies(t *testing.T) {
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(2, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetConstraintHandler(DebRules[float64]())
+	s.SetComparator(Lexicographic[float64](0))
+	err = s.SyncUpdateRecords([]Record[float64]{{Fitness: 1, Keys: []float64{5}}, {Fitness: 1, Keys: []float64{1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k := s.GlobalRecord().Keys; len(k) != 1 || k[0] != 1 {
+		t.Fatalf("global best has Keys %v, want [1]", k)
+	}
+	err = s.SyncUpdateRecords([]Record[float64]{{Fitness: 0, Violation: 1}, {Fitness: 2, Keys: []float64{0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := s.GlobalRecord(); r.Fitness != 1 || r.Keys[0] != 1 {
+		t.Fatalf("global best is %+v, want the feasible record with fitness 1 and Keys [1]", r)
+	}
+}
@@ -0,0 +1,267 @@
+package pso
+
+import (
+	"fmt"
+	"math"
+)
+
+//Config describes a swarm for NewSwarm and Configure.  Every field is named so values can't be swapped by accident like
+//with the long Set methods.
+type Config[T Float] struct {
+	//Seed is the seed NewSwarm passes to CreateSwarm.  Configure doesn't use it.
+	Seed int
+	//Mode is how the particles are updated.  Custom mode needs Updater and Binary mode uses Transfer.
+	Mode     Mode
+	Updater  VelocityUpdater[T]
+	Transfer Transfer
+	//Particles is the number of particles and Dims the number of dimensions.
+	Particles, Dims int
+	//Cognative and Social are how hard particles are pulled toward their personal and informant bests.
+	//Constriction mode needs Cognative + Social > 4.
+	Cognative, Social T
+	//Vmax is the max velocity.  It is the vmax reduction gamma in DynamicInertiaMaxVelReduction mode.
+	Vmax T
+	//MinStart and MaxStart are where new and resetted particles start.  They hold one value for every dimension or a value
+	//for each dimension.  They aren't used in Binary mode.
+	MinStart, MaxStart []T
+	//AlphaMax and InertiaMax are the max alpha and inertia given to new and resetted particles.  The Set methods that don't take them use .5.
+	AlphaMax, InertiaMax T
+	//Topology is the neighborhood topology.  nil is Star.
+	Topology Topology
+	//Lower and Upper are the search bounds (see SetBounds) and Boundary is how they are enforced.  nil for both is no bounds.
+	Lower, Upper []T
+	Boundary     Boundary
+	//Max is true to maximize fitness.  See SetFitness.
+	Max bool
+}
+
+//ConfigError is returned when a Config isn't valid.
+type ConfigError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return e.Field + " " + e.Reason
+}
+
+//Validate returns a *ConfigError for the first field of c that isn't valid.
+func (c Config[T]) Validate() error {
+	err := c.validatemode()
+	if err != nil {
+		return err
+	}
+	var m Mode
+	switch {
+	case c.Particles <= 0:
+		return &ConfigError{"Particles", fmt.Sprintf("needs to be > 0, got %d", c.Particles)}
+	case c.Dims <= 0:
+		return &ConfigError{"Dims", fmt.Sprintf("needs to be > 0, got %d", c.Dims)}
+	case !(c.Cognative >= 0):
+		return &ConfigError{"Cognative", fmt.Sprintf("can't be negative, got %v", c.Cognative)}
+	case !(c.Social >= 0):
+		return &ConfigError{"Social", fmt.Sprintf("can't be negative, got %v", c.Social)}
+	case c.Cognative == 0 && c.Social == 0:
+		return &ConfigError{"Social", "and Cognative can't both be zero"}
+	case !(c.Vmax > 0):
+		return &ConfigError{"Vmax", fmt.Sprintf("needs to be > 0, got %v", c.Vmax)}
+	case !(c.AlphaMax >= 0):
+		return &ConfigError{"AlphaMax", fmt.Sprintf("can't be negative, got %v", c.AlphaMax)}
+	case !(c.InertiaMax >= 0):
+		return &ConfigError{"InertiaMax", fmt.Sprintf("can't be negative, got %v", c.InertiaMax)}
+	}
+	if c.Mode != m.Binary() {
+		err = c.validatestart()
+		if err != nil {
+			return err
+		}
+	}
+	if c.Lower == nil && c.Upper == nil {
+		return nil
+	}
+	if len(c.Lower) != c.Dims || len(c.Upper) != c.Dims {
+		return &ConfigError{"Lower", fmt.Sprintf("and Upper need %d values, got %d and %d", c.Dims, len(c.Lower), len(c.Upper))}
+	}
+	for i := range c.Lower {
+		if !(c.Lower[i] < c.Upper[i]) {
+			return &ConfigError{fmt.Sprintf("Lower[%d]", i), fmt.Sprintf("needs to be < Upper[%d], got %v and %v", i, c.Lower[i], c.Upper[i])}
+		}
+	}
+	return nil
+}
+
+//validatemode checks the fields that go with the mode.
+func (c Config[T]) validatemode() error {
+	var m Mode
+	switch {
+	case c.Mode < m.Vanilla() || c.Mode > m.Binary():
+		return &ConfigError{"Mode", fmt.Sprintf("%d is not a Mode", c.Mode)}
+	case c.Mode == m.Custom() && c.Updater == nil:
+		return &ConfigError{"Updater", "needs to be set in Custom mode"}
+	case c.Mode == m.Constriction() && !(c.Cognative+c.Social > 4):
+		return &ConfigError{"Social", fmt.Sprintf("+ Cognative needs to be > 4 in Constriction mode, got %v", c.Cognative+c.Social)}
+	}
+	return nil
+}
+
+//constriction returns Clerc's constriction coefficient 2/|2-phi-sqrt(phi^2-4phi)| where phi is cognative + social.
+//It is only used in Constriction mode where phi > 4.
+func constriction[T Float](cognative, social T) T {
+	phi := float64(cognative + social)
+	return T(math.Abs(2 / (2 - phi - math.Sqrt(phi*phi-4*phi))))
+}
+
+func (c Config[T]) validatestart() error {
+	for _, f := range []struct {
+		name   string
+		values []T
+	}{{"MinStart", c.MinStart}, {"MaxStart", c.MaxStart}} {
+		if len(f.values) != 1 && len(f.values) != c.Dims {
+			return &ConfigError{f.name, fmt.Sprintf("needs 1 or %d values, got %d", c.Dims, len(f.values))}
+		}
+		for i, v := range f.values {
+			if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				return &ConfigError{fmt.Sprintf("%s[%d]", f.name, i), fmt.Sprintf("needs to be finite, got %v", v)}
+			}
+		}
+	}
+	for i := 0; i < c.Dims; i++ {
+		min, max := c.MinStart[0], c.MaxStart[0]
+		if len(c.MinStart) > 1 {
+			min = c.MinStart[i]
+		}
+		if len(c.MaxStart) > 1 {
+			max = c.MaxStart[i]
+		}
+		if !(min < max) {
+			return &ConfigError{"MinStart", fmt.Sprintf("needs to be < MaxStart in dimension %d, got %v and %v", i, min, max)}
+		}
+	}
+	return nil
+}
+
+//Option changes a Config.  Options are passed to NewSwarm.
+type Option[T Float] func(c *Config[T])
+
+//WithSeed sets Config.Seed.
+func WithSeed[T Float](seed int) Option[T] { return func(c *Config[T]) { c.Seed = seed } }
+
+//WithMode sets Config.Mode.
+func WithMode[T Float](mode Mode) Option[T] { return func(c *Config[T]) { c.Mode = mode } }
+
+//WithUpdater sets Config.Updater and Custom mode.
+func WithUpdater[T Float](u VelocityUpdater[T]) Option[T] {
+	return func(c *Config[T]) { c.Updater = u; c.Mode.Custom() }
+}
+
+//WithTransfer sets Config.Transfer.
+func WithTransfer[T Float](t Transfer) Option[T] { return func(c *Config[T]) { c.Transfer = t } }
+
+//WithParticles sets Config.Particles.
+func WithParticles[T Float](n int) Option[T] { return func(c *Config[T]) { c.Particles = n } }
+
+//WithDims sets Config.Dims.
+func WithDims[T Float](dims int) Option[T] { return func(c *Config[T]) { c.Dims = dims } }
+
+//WithCognative sets Config.Cognative.
+func WithCognative[T Float](cognative T) Option[T] {
+	return func(c *Config[T]) { c.Cognative = cognative }
+}
+
+//WithSocial sets Config.Social.
+func WithSocial[T Float](social T) Option[T] { return func(c *Config[T]) { c.Social = social } }
+
+//WithVmax sets Config.Vmax.
+func WithVmax[T Float](vmax T) Option[T] { return func(c *Config[T]) { c.Vmax = vmax } }
+
+//WithStart sets Config.MinStart and Config.MaxStart to the same range for every dimension.
+func WithStart[T Float](min, max T) Option[T] {
+	return func(c *Config[T]) { c.MinStart, c.MaxStart = []T{min}, []T{max} }
+}
+
+//WithAlphaMax sets Config.AlphaMax.
+func WithAlphaMax[T Float](alphamax T) Option[T] { return func(c *Config[T]) { c.AlphaMax = alphamax } }
+
+//WithInertiaMax sets Config.InertiaMax.
+func WithInertiaMax[T Float](inertiamax T) Option[T] {
+	return func(c *Config[T]) { c.InertiaMax = inertiamax }
+}
+
+//WithTopology sets Config.Topology.
+func WithTopology[T Float](t Topology) Option[T] { return func(c *Config[T]) { c.Topology = t } }
+
+//WithBounds sets Config.Lower, Config.Upper and Config.Boundary.
+func WithBounds[T Float](lower, upper []T, b Boundary) Option[T] {
+	return func(c *Config[T]) { c.Lower, c.Upper, c.Boundary = lower, upper, b }
+}
+
+//WithMax sets Config.Max.
+func WithMax[T Float](max bool) Option[T] { return func(c *Config[T]) { c.Max = max } }
+
+//NewSwarm creates a swarm with CreateSwarm(cfg.Seed) and sets it up with cfg after opts are applied to it.
+//It returns a *ConfigError if the Config isn't valid.
+func NewSwarm[T Float](cfg Config[T], opts ...Option[T]) (*Swarm[T], error) {
+	for _, o := range opts {
+		o(&cfg)
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	s := CreateSwarm[T](cfg.Seed)
+	s.configure(cfg)
+	return s, nil
+}
+
+//Configure validates cfg and sets the swarm up with it.  The particles are remade and the global best is cleared.
+//It returns a *ConfigError and leaves the swarm alone if cfg isn't valid.
+func (s *Swarm[T]) Configure(cfg Config[T]) error {
+	err := cfg.Validate()
+	if err != nil {
+		return err
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.configure(cfg)
+	return nil
+}
+
+//Validate returns a *ConfigError if the swarm isn't set up in a valid way, like before one of its Set methods is called.
+func (s *Swarm[T]) Validate() error {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.config().Validate()
+}
+
+//Config returns the Config the swarm is set up with.  Seed is always zero and Updater is only set in Custom mode.
+func (s *Swarm[T]) Config() Config[T] {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.config()
+}
+
+func (s *Swarm[T]) config() Config[T] {
+	c := Config[T]{
+		Mode:       s.mode,
+		Transfer:   s.transfer,
+		Particles:  len(s.particles),
+		Dims:       len(s.globalposition),
+		Cognative:  s.cognative,
+		Social:     s.social,
+		Vmax:       s.vmax,
+		MinStart:   append([]T(nil), s.xminstart...),
+		MaxStart:   append([]T(nil), s.xmaxstart...),
+		AlphaMax:   s.alphamax,
+		InertiaMax: s.inertiamax,
+		Topology:   s.topology,
+		Lower:      append([]T(nil), s.lower...),
+		Upper:      append([]T(nil), s.upper...),
+		Boundary:   s.boundary,
+		Max:        s.max,
+	}
+	var m Mode
+	if s.mode == m.Custom() {
+		c.Updater = s.custom
+	}
+	return c
+}
@@ -0,0 +1,123 @@
+package pso
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+//TestConfigErrors checks that invalid setups return a *ConfigError for the right field and leave the swarm alone.
+func TestConfigErrors(t *testing.T) {
+	field := func(err error) string {
+		var ce *ConfigError
+		if !errors.As(err, &ce) {
+			t.Fatalf("got %v, want a *ConfigError", err)
+		}
+		return ce.Field
+	}
+	s := CreateSwarm64(1)
+	if f := field(s.Validate()); f != "Mode" {
+		t.Fatalf("Validate of a swarm that isn't set up named %s, want Mode", f)
+	}
+	if f := field(s.ChangeMinStart(-1)); f != "MinStart" {
+		t.Fatalf("ChangeMinStart before setup named %s, want MinStart", f)
+	}
+	if f := field(s.SetConstriction(10, 2, 2, 2, 1, -5, 5)); f != "Social" {
+		t.Fatalf("SetConstriction with cognative + social = 4 named %s, want Social", f)
+	}
+	err := s.SetConstriction(10, 2, 2.05, 2.05, 1, -5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := field(s.ChangeMinStart(6)); f != "MinStart" {
+		t.Fatalf("ChangeMinStart above MaxStart named %s, want MinStart", f)
+	}
+	if f := field(s.ChangeMaxStart(1, 2, 3)); f != "MaxStart" {
+		t.Fatalf("ChangeMaxStart with 3 values for 2 dims named %s, want MaxStart", f)
+	}
+	var m Mode
+	if f := field(s.ChangeMode(m.Custom())); f != "Updater" {
+		t.Fatalf("ChangeMode(Custom) without an updater named %s, want Updater", f)
+	}
+	c := s.Config()
+	if c.Mode != m.Constriction() || c.MinStart[0] != -5 || c.MaxStart[0] != 5 {
+		t.Fatalf("swarm changed after the errors: %+v", c)
+	}
+	if err = s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	c.Lower, c.Upper = []float64{1, 1}, []float64{0, 0}
+	if _, err = NewSwarm64(c); field(err) != "Lower[0]" {
+		t.Fatalf("NewSwarm with lower > upper returned %v, want a *ConfigError for Lower[0]", err)
+	}
+}
+
+//TestSharedTopology checks that swarms built from one Config with a RandomInformants topology don't share its links.
+func TestSharedTopology(t *testing.T) {
+	var m Mode
+	cfg := Config64{
+		Seed:       4,
+		Mode:       m.ConstantInertia(),
+		Particles:  12,
+		Dims:       3,
+		Cognative:  1.49445,
+		Social:     1.49445,
+		Vmax:       1,
+		MinStart:   []float64{-5},
+		MaxStart:   []float64{5},
+		AlphaMax:   .5,
+		InertiaMax: .7,
+		Topology:   RandomInformants(3),
+	}
+	sphere := func(x []float64) float64 { return x[0]*x[0] + x[1]*x[1] + x[2]*x[2] }
+	run := func(s *Swarm64) {
+		positions := s.positions()
+		fitnesses := make([]float64, len(positions))
+		for i := range positions {
+			fitnesses[i] = sphere(positions[i])
+		}
+		err := s.SyncUpdate(fitnesses)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	alone, err := NewSwarm64(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k < 20; k++ {
+		run(alone)
+	}
+	a, err := NewSwarm64(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSwarm64(cfg, WithSeed[float64](5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewSwarm64(a.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for _, s := range []*Swarm64{b, c} {
+		wg.Add(1)
+		go func(s *Swarm64) {
+			defer wg.Done()
+			for k := 0; k < 20; k++ {
+				run(s)
+			}
+		}(s)
+	}
+	for k := 0; k < 20; k++ {
+		run(a)
+	}
+	wg.Wait()
+	want, got := alone.positions(), a.positions()
+	for i := range want {
+		if !equal(want[i], got[i]) {
+			t.Fatalf("particle %d is at %v, want %v like the swarm that ran alone", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package pso
+
+import (
+	"encoding/json"
+	"math"
+)
+
+//Record is the result of evaluating one position.
+//
+//Violation is how much the position violates the constraints. Zero means the position is feasible.
+//It is up to the user how violations of several constraints are added up into one.
+type Record[T Float] struct {
+	Fitness   T
+	Violation T
+	//Keys are secondary keys for a Comparator, like the number of parameters of a model.  They are nil unless the
+	//records are given with SyncUpdateRecords, AsyncUpdateRecord or TellRecord.
+	Keys []T
+	//Err is set if the evaluation failed.  It is handled with the Failure set with SetFailurePolicy.
+	Err error
+}
+
+//Feasible returns true if the record doesn't violate any constraints.
+func (r Record[T]) Feasible() bool {
+	return r.Violation <= 0
+}
+
+//ConstraintHandler decides which of two records is better when the swarm is given constraint violations.
+//It is used for the personal bests, the informant bests and the global best.
+type ConstraintHandler[T Float] interface {
+	//Better returns true if a is better than b.  max is true if the swarm is maximizing and k is the swarm's iteration.
+	Better(a, b Record[T], max bool, k int) bool
+
+	//Update is called once after every swarm update with the global best record.
+	Update(best Record[T], k int)
+}
+
+//SetConstraintHandler sets how the swarm compares records with constraint violations.
+//If it is nil (default) violations are ignored and only fitness is compared.
+func (s *Swarm[T]) SetConstraintHandler(h ConstraintHandler[T]) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.constraint = h
+}
+
+//better returns true if a is a better record than b.  The Comparator only breaks ties of the ConstraintHandler.
+func (s *Swarm[T]) better(a, b Record[T]) bool {
+	if s.constraint != nil {
+		if s.constraint.Better(a, b, s.max, s.k) {
+			return true
+		}
+		if s.comparator == nil || s.constraint.Better(b, a, s.max, s.k) {
+			return false
+		}
+	}
+	if s.comparator != nil {
+		return s.comparator.Better(a, b, s.max)
+	}
+	if s.max {
+		return a.Fitness > b.Fitness
+	}
+	return a.Fitness < b.Fitness
+}
+
+//penalized compares a and b after the penalty is taken out of their fitness.
+func penalized[T Float](a, b Record[T], pa, pb float64, max bool) bool {
+	if max {
+		return float64(a.Fitness)-pa > float64(b.Fitness)-pb
+	}
+	return float64(a.Fitness)+pa < float64(b.Fitness)+pb
+}
+
+//noviolation returns zero for records without violations so an infinite violation times a zero penalty isn't NaN.
+func noviolation(penalty float64, violation float64) float64 {
+	if violation <= 0 {
+		return 0
+	}
+	return penalty * violation
+}
+
+type staticpenalty[T Float] struct {
+	r float64
+}
+
+//StaticPenalty returns a ConstraintHandler that adds r*violation to the fitness (takes it away when maximizing).
+func StaticPenalty[T Float](r T) ConstraintHandler[T] {
+	return staticpenalty[T]{r: float64(r)}
+}
+
+func (p staticpenalty[T]) Better(a, b Record[T], max bool, k int) bool {
+	return penalized(a, b, noviolation(p.r, float64(a.Violation)), noviolation(p.r, float64(b.Violation)), max)
+}
+func (staticpenalty[T]) Update(best Record[T], k int) {}
+
+type dynamicpenalty[T Float] struct {
+	c, alpha, beta float64
+}
+
+//DynamicPenalty returns the Joines and Houck ConstraintHandler.  The penalty is (c*k)^alpha * violation^beta so it grows as the swarm goes on.
+//Common values are c=.5, alpha=2, beta=2.
+func DynamicPenalty[T Float](c, alpha, beta T) ConstraintHandler[T] {
+	return dynamicpenalty[T]{c: float64(c), alpha: float64(alpha), beta: float64(beta)}
+}
+
+func (p dynamicpenalty[T]) Better(a, b Record[T], max bool, k int) bool {
+	w := math.Pow(p.c*float64(k), p.alpha)
+	return penalized(a, b,
+		noviolation(w, math.Pow(math.Max(float64(a.Violation), 0), p.beta)),
+		noviolation(w, math.Pow(math.Max(float64(b.Violation), 0), p.beta)), max)
+}
+func (dynamicpenalty[T]) Update(best Record[T], k int) {}
+
+type adaptivepenalty[T Float] struct {
+	lambda, beta1, beta2 float64
+	window               int
+	feasible, infeasible int
+}
+
+//AdaptivePenalty returns the Hadj-Alouane and Bean ConstraintHandler.  The penalty is lambda*violation.
+//If the global best was feasible for the last window updates lambda is divided by beta1.
+//If it was infeasible for the last window updates lambda is multiplied by beta2.  beta1 and beta2 should be > 1 and not equal.
+func AdaptivePenalty[T Float](lambda, beta1, beta2 T, window int) ConstraintHandler[T] {
+	if window < 1 {
+		window = 1
+	}
+	return &adaptivepenalty[T]{lambda: float64(lambda), beta1: float64(beta1), beta2: float64(beta2), window: window}
+}
+
+func (p *adaptivepenalty[T]) Better(a, b Record[T], max bool, k int) bool {
+	return penalized(a, b, noviolation(p.lambda, float64(a.Violation)), noviolation(p.lambda, float64(b.Violation)), max)
+}
+
+//adaptivestate is the part of an adaptivepenalty that changes during a run.
+type adaptivestate struct {
+	Lambda               number
+	Feasible, Infeasible int
+}
+
+//MarshalBinary saves lambda and the feasible and infeasible counts so checkpoints can be restarted exactly.
+func (p *adaptivepenalty[T]) MarshalBinary() ([]byte, error) {
+	return json.Marshal(adaptivestate{Lambda: number(p.lambda), Feasible: p.feasible, Infeasible: p.infeasible})
+}
+
+//UnmarshalBinary loads the state saved with MarshalBinary.
+func (p *adaptivepenalty[T]) UnmarshalBinary(data []byte) error {
+	var a adaptivestate
+	err := json.Unmarshal(data, &a)
+	if err != nil {
+		return err
+	}
+	p.lambda, p.feasible, p.infeasible = float64(a.Lambda), a.Feasible, a.Infeasible
+	return nil
+}
+
+func (p *adaptivepenalty[T]) Update(best Record[T], k int) {
+	if best.Feasible() {
+		p.feasible++
+		p.infeasible = 0
+	} else {
+		p.infeasible++
+		p.feasible = 0
+	}
+	if p.feasible >= p.window {
+		p.lambda /= p.beta1
+		p.feasible = 0
+	}
+	if p.infeasible >= p.window {
+		p.lambda *= p.beta2
+		p.infeasible = 0
+	}
+}
+
+type debrules[T Float] struct{}
+
+//DebRules returns the ConstraintHandler that uses Deb's feasibility rules.
+//A feasible record beats an infeasible one. Two feasible records are compared by fitness.
+//Two infeasible records are compared by violation.
+func DebRules[T Float]() ConstraintHandler[T] {
+	return debrules[T]{}
+}
+
+func (debrules[T]) Better(a, b Record[T], max bool, k int) bool {
+	af, bf := a.Feasible(), b.Feasible()
+	switch {
+	case af && bf:
+		if max {
+			return a.Fitness > b.Fitness
+		}
+		return a.Fitness < b.Fitness
+	case af != bf:
+		return af
+	}
+	return a.Violation < b.Violation
+}
+func (debrules[T]) Update(best Record[T], k int) {}
+
+type epsilonconstrained[T Float] struct {
+	epsilon0, cp float64
+	tc           int
+}
+
+//EpsilonConstrained returns Takahama's epsilon constrained ConstraintHandler.
+//Records with violations at or below epsilon are compared by fitness, otherwise by violation.
+//Epsilon starts at epsilon0 and goes down to 0 at iteration tc as epsilon0*(1-k/tc)^cp.
+func EpsilonConstrained[T Float](epsilon0, cp T, tc int) ConstraintHandler[T] {
+	return epsilonconstrained[T]{epsilon0: float64(epsilon0), cp: float64(cp), tc: tc}
+}
+
+func (e epsilonconstrained[T]) epsilon(k int) float64 {
+	if k >= e.tc {
+		return 0
+	}
+	return e.epsilon0 * math.Pow(1-float64(k)/float64(e.tc), e.cp)
+}
+
+func (e epsilonconstrained[T]) Better(a, b Record[T], max bool, k int) bool {
+	eps := e.epsilon(k)
+	av, bv := math.Max(float64(a.Violation), 0), math.Max(float64(b.Violation), 0)
+	if (av <= eps && bv <= eps) || av == bv {
+		if max {
+			return a.Fitness > b.Fitness
+		}
+		return a.Fitness < b.Fitness
+	}
+	return av < bv
+}
+func (epsilonconstrained[T]) Update(best Record[T], k int) {}
@@ -0,0 +1,49 @@
+package pso
+
+import (
+	"bytes"
+	"testing"
+)
+
+//TestSaveAdaptivePenalty checks that a swarm with an AdaptivePenalty loaded from a checkpoint ends like one that wasn't stopped.
+func TestSaveAdaptivePenalty(t *testing.T) {
+	newswarm := func() *Swarm64 {
+		s := CreateSwarm64(3)
+		err := s.SetConstantInertia(10, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetConstraintHandler(AdaptivePenalty(1.0, 2, 3, 2))
+		return s
+	}
+	run := func(s *Swarm64, iterations int) {
+		for it := 0; it < iterations; it++ {
+			fitnesses := make([]float64, len(s.particles))
+			violations := make([]float64, len(s.particles))
+			for i := range s.particles {
+				x := s.particles[i].position
+				fitnesses[i], violations[i] = x[0]*x[0]+x[1]*x[1], 1-x[0]-x[1]
+			}
+			err := s.SyncUpdateConstrained(fitnesses, violations)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	whole := newswarm()
+	run(whole, 40)
+	saved := newswarm()
+	run(saved, 20)
+	var buf bytes.Buffer
+	err := saved.Save(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := newswarm()
+	err = loaded.Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run(loaded, 20)
+	sameswarm(t, loaded, whole)
+}
@@ -0,0 +1,185 @@
+package pso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+//Objective is a fitness function that can fail and should stop early when ctx is done.
+type Objective[T Float] func(ctx context.Context, position []T) (T, error)
+
+//EvaluationError is returned when the objective fails on a particle, or when a fitness is NaN or ±Inf and the Failure is Abort.
+type EvaluationError struct {
+	Particle int
+	Err      error
+}
+
+func (e *EvaluationError) Error() string {
+	return fmt.Sprintf("Evaluating particle %d: %v", e.Particle, e.Err)
+}
+
+//Unwrap returns the error the objective returned.
+func (e *EvaluationError) Unwrap() error { return e.Err }
+
+//Evaluator runs an Objective on many positions at once with a pool of workers.
+//
+//Fitnesses are put back in the order of the positions no matter which evaluation finishes first,
+//so a seeded swarm does the same run with any number of workers.
+type Evaluator[T Float] struct {
+	objective Objective[T]
+	workers   int
+}
+
+//NewEvaluator returns an Evaluator that runs objective on workers goroutines.  workers <= 0 uses GOMAXPROCS.
+func NewEvaluator[T Float](objective Objective[T], workers int) *Evaluator[T] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Evaluator[T]{objective: objective, workers: workers}
+}
+
+//Evaluate runs the objective on every position and returns the fitnesses in the same order.
+//
+//If an evaluation fails the ctx passed to the rest is canceled and an *EvaluationError for the lowest
+//particle that failed is returned.  If ctx is done first its error is returned.
+func (e *Evaluator[T]) Evaluate(ctx context.Context, positions [][]T) ([]T, error) {
+	fitnesses, _, err := e.evaluate(ctx, positions, false)
+	return fitnesses, err
+}
+
+//evaluate runs the objective on every position.  If keepgoing is true a failed evaluation doesn't stop the rest
+//and the errors are returned with the fitnesses.  Otherwise it is like Evaluate.
+func (e *Evaluator[T]) evaluate(ctx context.Context, positions [][]T, keepgoing bool) ([]T, []error, error) {
+	fitnesses := make([]T, len(positions))
+	errs := make([]error, len(positions))
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workers := e.workers
+	if workers > len(positions) {
+		workers = len(positions)
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f, err := e.objective(ctx, positions[i])
+				if err != nil {
+					errs[i] = err
+					if !keepgoing {
+						cancel()
+					}
+					continue
+				}
+				fitnesses[i] = f
+			}
+		}()
+	}
+feed:
+	for i := range positions {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if keepgoing {
+		if parent.Err() != nil {
+			return nil, nil, parent.Err()
+		}
+		return fitnesses, errs, nil
+	}
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			return nil, nil, &EvaluationError{Particle: i, Err: err}
+		}
+	}
+	if parent.Err() != nil {
+		return nil, nil, parent.Err()
+	}
+	for i, err := range errs {
+		if err != nil {
+			return nil, nil, &EvaluationError{Particle: i, Err: err}
+		}
+	}
+	return fitnesses, nil, nil
+}
+
+//Step evaluates every particle with e and passes the fitnesses to SyncUpdate.
+//
+//Failed evaluations are handled with the Failure set with SetFailurePolicy.
+//The swarm isn't changed if ctx is done or an evaluation fails and the Failure is Abort.  If a particle is moved by
+//something else while it is being evaluated ErrStaleTicket is returned and the swarm isn't changed either.
+func (s *Swarm[T]) Step(ctx context.Context, e *Evaluator[T]) error {
+	records := make([]Record[T], s.NumOfParticles())
+	positions, err := s.evaluate(ctx, e, records)
+	if err != nil {
+		return err
+	}
+	return s.syncupdate(records, positions, false)
+}
+
+//OptimizeContext is Optimize with the evaluations of each iteration run concurrently by e.
+//
+//It stops with ctx's error if ctx is done and with an *EvaluationError if the objective fails and the Failure is Abort.
+//The Result up to the last full iteration is returned with the error.
+func (s *Swarm[T]) OptimizeContext(ctx context.Context, e *Evaluator[T], budget Budget[T]) (Result[T], error) {
+	return s.optimize(budget, func(records []Record[T]) ([][]T, error) {
+		err := ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+		return s.evaluate(ctx, e, records)
+	})
+}
+
+//evaluate fills in records with e and returns the positions they were evaluated at.  Failed evaluations are put in the
+//records for the Failure policy.  If the Failure is Abort the first one stops the rest and its *EvaluationError is returned.
+func (s *Swarm[T]) evaluate(ctx context.Context, e *Evaluator[T], records []Record[T]) ([][]T, error) {
+	abort := s.aborts()
+	positions := s.positions()
+	evaluated := make([][]T, len(positions))
+	for i := range positions {
+		evaluated[i] = append([]T(nil), positions[i]...)
+	}
+	fitnesses, errs, err := e.evaluate(ctx, evaluated, !abort)
+	if err != nil {
+		var ee *EvaluationError
+		if errors.As(err, &ee) {
+			s.mux.Lock()
+			s.failures.Errors++
+			s.failures.Aborted++
+			s.mux.Unlock()
+		}
+		return nil, err
+	}
+	if len(fitnesses) != len(records) {
+		return nil, errors.New("Number of particles changed during evaluation")
+	}
+	for i := range records {
+		records[i] = Record[T]{Fitness: fitnesses[i]}
+		if errs != nil {
+			records[i].Err = errs[i]
+		}
+	}
+	return positions, nil
+}
+
+//positions returns copies of the particle positions.
+func (s *Swarm[T]) positions() [][]T {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	positions := make([][]T, len(s.particles))
+	for i := range positions {
+		positions[i] = s.particles[i].copyposition()
+	}
+	return positions
+}
@@ -0,0 +1,41 @@
+package pso
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+//TestEvaluate checks that Evaluate returns the fitnesses in the order of the positions and the error of the lowest particle that failed.
+func TestEvaluate(t *testing.T) {
+	failed := errors.New("failed")
+	e := NewEvaluator(func(ctx context.Context, x []float64) (float64, error) {
+		if x[0] == 7 || x[0] == 30 {
+			return 0, failed
+		}
+		return 2 * x[0], nil
+	}, 4)
+	positions := make([][]float64, 50)
+	for i := range positions {
+		positions[i] = []float64{float64(i)}
+	}
+	_, err := e.Evaluate(context.Background(), positions)
+	var ee *EvaluationError
+	if !errors.As(err, &ee) || ee.Particle != 7 || !errors.Is(err, failed) {
+		t.Fatalf("Evaluate returned %v, want the error of particle 7", err)
+	}
+	fitnesses, err := e.Evaluate(context.Background(), positions[:7])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, f := range fitnesses {
+		if f != 2*float64(i) {
+			t.Fatalf("fitness %d is %v, want %v", i, f, 2*float64(i))
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = e.Evaluate(ctx, positions[:7]); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Evaluate with a canceled ctx returned %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,126 @@
+package pso
+
+import (
+	"errors"
+	"math"
+)
+
+//Failure is the flag for what the swarm does with a record whose fitness is NaN or ±Inf or whose evaluation failed (Record.Err is set).
+//
+//If no Failure was set then Worst is used.
+type Failure int32
+
+//Worst sets Worst Failure. The record is never a personal, informant or global best and the particle moves on like normal.
+func (f *Failure) Worst() Failure { *f = Failure(1); return *f }
+
+//Reevaluate sets Reevaluate Failure. The particle stays where it is so its position is evaluated again on the next update.
+//After the number of retries passed to SetFailurePolicy it is handled like Worst.
+func (f *Failure) Reevaluate() Failure { *f = Failure(2); return *f }
+
+//Reset sets Reset Failure. The particle is reset like ResetParticle and doesn't move.
+func (f *Failure) Reset() Failure { *f = Failure(3); return *f }
+
+//Abort sets Abort Failure. The update returns an *EvaluationError and the swarm isn't changed.
+func (f *Failure) Abort() Failure { *f = Failure(4); return *f }
+
+//ErrNonFinite is the Err of the *EvaluationError returned when a fitness is NaN or ±Inf and the Failure is Abort.
+var ErrNonFinite = errors.New("Fitness is NaN or Inf")
+
+//Failures counts the bad records the swarm was given and what was done with them.
+type Failures struct {
+	//NaN, Inf and Errors count records with a NaN fitness, a ±Inf fitness and a failed evaluation.
+	NaN, Inf, Errors int
+	//Worst, Reevaluated, Reset and Aborted count what the Failure policy did with them.
+	Worst, Reevaluated, Reset, Aborted int
+}
+
+//add counts what was wrong with r.
+func (c *Failures) add(fitness float64, err error) {
+	switch {
+	case err != nil:
+		c.Errors++
+	case math.IsNaN(fitness):
+		c.NaN++
+	default:
+		c.Inf++
+	}
+}
+
+//SetFailurePolicy sets what the swarm does with NaN or ±Inf fitnesses and failed evaluations.
+//retries is how many times in a row a particle is evaluated again with Reevaluate.  It is ignored by the other policies.
+func (s *Swarm[T]) SetFailurePolicy(f Failure, retries int) error {
+	if retries < 0 {
+		return errors.New("Retries can't be negative")
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.failure = f
+	s.retries = retries
+	return nil
+}
+
+//Failures returns how many bad records the swarm was given and what was done with them.
+func (s *Swarm[T]) Failures() Failures {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.failures
+}
+
+//aborts returns true if the Failure is Abort.
+func (s *Swarm[T]) aborts() bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	var f Failure
+	return s.failure == f.Abort()
+}
+
+//bad returns true if r has a NaN or ±Inf fitness or a failed evaluation.
+func bad[T Float](r Record[T]) bool {
+	f := float64(r.Fitness)
+	return r.Err != nil || math.IsNaN(f) || math.IsInf(f, 0)
+}
+
+//abort returns an *EvaluationError for the first bad record if the Failure is Abort.  first is the index of records[0].
+func (s *Swarm[T]) abort(first int, records ...Record[T]) error {
+	var f Failure
+	if s.failure != f.Abort() {
+		return nil
+	}
+	for i, r := range records {
+		if !bad(r) {
+			continue
+		}
+		s.failures.add(float64(r.Fitness), r.Err)
+		s.failures.Aborted++
+		err := r.Err
+		if err == nil {
+			err = ErrNonFinite
+		}
+		return &EvaluationError{Particle: first + i, Err: err}
+	}
+	return nil
+}
+
+//fail handles bad record r of particle i with the Failure policy.  It returns true if the particle should still be moved.
+func (s *Swarm[T]) fail(i int, r Record[T]) bool {
+	s.failures.add(float64(r.Fitness), r.Err)
+	p := &s.particles[i]
+	var f Failure
+	switch s.failure {
+	case f.Reevaluate():
+		if p.retries < s.retries {
+			p.retries++
+			s.failures.Reevaluated++
+			return false
+		}
+	case f.Reset():
+		minstart, maxstart := s.startrange()
+		p.reset(s.vmax, minstart, maxstart, s.alphamax, s.inertiamax)
+		s.startbits(p)
+		s.failures.Reset++
+		return false
+	}
+	p.retries = 0
+	s.failures.Worst++
+	return true
+}
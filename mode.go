@@ -18,4 +18,29 @@ func (m *Mode) Constriction() Mode { *m = Mode(4); return *m }
 //DynamicInertiaMaxVelReduction sets DynamicInertiaMaxVelReduction Mode
 func (m *Mode) DynamicInertiaMaxVelReduction() Mode { *m = Mode(5); return *m }
 
-//func (m *Mode) customfunction()Mode{ *m=Mode(6);return *m}
+//Custom sets Custom Mode. The swarm uses the VelocityUpdater passed to SetUpdater.
+func (m *Mode) Custom() Mode { *m = Mode(6); return *m }
+
+//Binary sets Binary Mode. Positions are bits and velocities are turned into the probability of a bit being set or flipped.
+func (m *Mode) Binary() Mode { *m = Mode(7); return *m }
+
+func (m Mode) String() string {
+	var f Mode
+	switch m {
+	case f.Vanilla():
+		return "Vanilla"
+	case f.ConstantInertia():
+		return "ConstantInertia"
+	case f.InertiaReduction():
+		return "InertiaReduction"
+	case f.Constriction():
+		return "Constriction"
+	case f.DynamicInertiaMaxVelReduction():
+		return "DynamicInertiaMaxVelReduction"
+	case f.Custom():
+		return "Custom"
+	case f.Binary():
+		return "Binary"
+	}
+	return "None"
+}
@@ -0,0 +1,363 @@
+package pso
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+//Pruning is the flag for how a MultiSwarm keeps its archive under its max size and picks leaders from it.
+type Pruning int32
+
+//CrowdingDistance sets CrowdingDistance Pruning. The most crowded members are removed and leaders are picked by binary tournament on crowding distance.
+func (p *Pruning) CrowdingDistance() Pruning { *p = Pruning(1); return *p }
+
+//AdaptiveGrid sets AdaptiveGrid Pruning. Objective space is split into a grid that adapts to the archive.
+//Members are removed from the fullest cells and leaders are picked from emptier cells more often.
+func (p *Pruning) AdaptiveGrid() Pruning { *p = Pruning(2); return *p }
+
+//ParetoPoint is a non dominated position and its objectives.
+type ParetoPoint[T Float] struct {
+	Position   []T
+	Objectives []T
+}
+
+//MultiSwarm is a multi objective particle swarm (MOPSO).  Every objective is minimized. Negate an objective to maximize it.
+//
+//The particles are moved with the mode and VelocityUpdater of the Swarm it was made from, but each particle
+//follows a leader picked from an archive of the non dominated positions found so far instead of the global best.
+//
+//It is safe for concurrent use.  It shares the lock of the Swarm it was made from.
+type MultiSwarm[T Float] struct {
+	s           *Swarm[T]
+	objectives  int
+	archivesize int
+	pruning     Pruning
+	divisions   int
+	archive     []ParetoPoint[T]
+	pbest       [][]T
+}
+
+//CreateMultiSwarm makes a MultiSwarm from s. s needs to be set up with one of its Set methods first.
+//Only the particles, mode, coefficients and bounds of s are used.
+//
+//objectives is the number of objectives and archivesize the max number of points kept in the Pareto archive.
+//The default Pruning is CrowdingDistance.
+func CreateMultiSwarm[T Float](s *Swarm[T], objectives, archivesize int) (*MultiSwarm[T], error) {
+	n := s.NumOfParticles()
+	if n == 0 {
+		return nil, errors.New("Swarm has no particles")
+	}
+	if objectives < 1 {
+		return nil, errors.New("Need at least one objective")
+	}
+	if archivesize < 1 {
+		return nil, errors.New("Archive size needs to be at least 1")
+	}
+	m := &MultiSwarm[T]{
+		s:           s,
+		objectives:  objectives,
+		archivesize: archivesize,
+		divisions:   10,
+		pbest:       make([][]T, n),
+	}
+	m.pruning.CrowdingDistance()
+	return m, nil
+}
+
+//SetPruning sets how the archive is pruned. divisions is the number of grid cells for each objective with AdaptiveGrid.
+func (m *MultiSwarm[T]) SetPruning(p Pruning, divisions int) {
+	m.s.mux.Lock()
+	defer m.s.mux.Unlock()
+	m.pruning = p
+	if divisions > 0 {
+		m.divisions = divisions
+	}
+}
+
+//NumOfParticles returns the number of particles in the swarm.
+func (m *MultiSwarm[T]) NumOfParticles() int {
+	return m.s.NumOfParticles()
+}
+
+//ParticlePosition returns the particle position of the index passed
+func (m *MultiSwarm[T]) ParticlePosition(index int) []T {
+	return m.s.ParticlePosition(index)
+}
+
+//SyncUpdate updates the swarm after all particles were tested.  objectives[i] holds the objectives of particle i.
+//Objectives that are NaN or ±Inf are handled with the Failure set on the swarm with SetFailurePolicy.
+//Until the archive has a point in it the particles follow their personal bests.
+func (m *MultiSwarm[T]) SyncUpdate(objectives [][]T) error {
+	s := m.s
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if len(objectives) != len(s.particles) || len(objectives) != len(m.pbest) {
+		return errors.New("Sizes of objectives and num of particles not the same")
+	}
+	for i := range objectives {
+		if len(objectives[i]) != m.objectives {
+			return errors.New("Wrong number of objectives")
+		}
+	}
+	records := make([]Record[T], len(objectives))
+	for i := range records {
+		records[i] = objectiverecord(objectives[i])
+	}
+	err := s.abort(0, records...)
+	if err != nil {
+		return err
+	}
+	hold := make([]bool, len(objectives))
+	for i := range objectives {
+		if bad(records[i]) {
+			hold[i] = !s.fail(i, records[i])
+			var f Failure
+			if s.failure == f.Reset() {
+				m.pbest[i] = nil
+			}
+			continue
+		}
+		p := &s.particles[i]
+		switch {
+		case m.pbest[i] == nil || dominates(objectives[i], m.pbest[i]):
+			m.pbest[i] = append(m.pbest[i][:0], objectives[i]...)
+			copy(p.indvbest, p.position)
+		case !dominates(m.pbest[i], objectives[i]) && s.rng.Intn(2) == 0:
+			copy(m.pbest[i], objectives[i])
+			copy(p.indvbest, p.position)
+		}
+		m.add(p.position, objectives[i])
+	}
+	var pick func() []T
+	if len(m.archive) > 0 {
+		pick = m.leaders()
+	}
+	for i := range s.particles {
+		switch {
+		case hold[i]:
+		case pick == nil:
+			s.move(i, s.particles[i].indvbest)
+		default:
+			s.move(i, pick())
+		}
+	}
+	s.k++
+	return nil
+}
+
+//objectiverecord returns a Record with the first objective that is NaN or ±Inf as its fitness so it can be handled with the Failure policy.
+func objectiverecord[T Float](objectives []T) Record[T] {
+	for _, o := range objectives {
+		r := Record[T]{Fitness: o}
+		if bad(r) {
+			return r
+		}
+	}
+	return Record[T]{}
+}
+
+//Optimize evaluates objective on every particle and calls SyncUpdate for iterations. It returns the Pareto front.
+func (m *MultiSwarm[T]) Optimize(objective func([]T) []T, iterations int) ([]ParetoPoint[T], error) {
+	for k := 0; k < iterations; k++ {
+		positions := m.s.positions()
+		objectives := make([][]T, len(positions))
+		for i := range objectives {
+			objectives[i] = objective(positions[i])
+		}
+		err := m.SyncUpdate(objectives)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.ParetoFront(), nil
+}
+
+//ParetoFront returns a copy of the archive sorted by the first objective.
+func (m *MultiSwarm[T]) ParetoFront() []ParetoPoint[T] {
+	m.s.mux.RLock()
+	defer m.s.mux.RUnlock()
+	front := make([]ParetoPoint[T], len(m.archive))
+	for i, a := range m.archive {
+		front[i] = ParetoPoint[T]{
+			Position:   append([]T(nil), a.Position...),
+			Objectives: append([]T(nil), a.Objectives...),
+		}
+	}
+	sort.Slice(front, func(i, j int) bool {
+		return front[i].Objectives[0] < front[j].Objectives[0]
+	})
+	return front
+}
+
+//dominates returns true if a is no worse than b in every objective and better in at least one.
+func dominates[T Float](a, b []T) bool {
+	better := false
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+		if a[i] < b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+//add puts the position in the archive if nothing in the archive dominates it.
+func (m *MultiSwarm[T]) add(position, objectives []T) {
+	for _, a := range m.archive {
+		if dominates(a.Objectives, objectives) || equal(a.Objectives, objectives) {
+			return
+		}
+	}
+	kept := m.archive[:0]
+	for _, a := range m.archive {
+		if !dominates(objectives, a.Objectives) {
+			kept = append(kept, a)
+		}
+	}
+	m.archive = append(kept, ParetoPoint[T]{
+		Position:   append([]T(nil), position...),
+		Objectives: append([]T(nil), objectives...),
+	})
+	if len(m.archive) > m.archivesize {
+		m.prune()
+	}
+}
+
+//prune removes one member from the archive.
+func (m *MultiSwarm[T]) prune() {
+	var remove int
+	var p Pruning
+	if m.pruning == p.AdaptiveGrid() {
+		cells, counts := m.grid()
+		fullest := cells[0]
+		for _, c := range cells {
+			if counts[c] > counts[fullest] || (counts[c] == counts[fullest] && c < fullest) {
+				fullest = c
+			}
+		}
+		members := make([]int, 0, counts[fullest])
+		for i, c := range cells {
+			if c == fullest {
+				members = append(members, i)
+			}
+		}
+		remove = members[m.s.rng.Intn(len(members))]
+	} else {
+		d := crowding(m.archive)
+		for i := range d {
+			if d[i] < d[remove] {
+				remove = i
+			}
+		}
+	}
+	m.archive = append(m.archive[:remove], m.archive[remove+1:]...)
+}
+
+//leaders returns a function that picks a leader position from the archive for a particle.
+func (m *MultiSwarm[T]) leaders() func() []T {
+	rng := m.s.rng
+	var p Pruning
+	if m.pruning == p.AdaptiveGrid() {
+		cells, counts := m.grid()
+		keys := make([]int, 0, len(counts))
+		for c := range counts {
+			keys = append(keys, c)
+		}
+		sort.Ints(keys)
+		var total float64
+		for _, c := range keys {
+			total += 10 / float64(counts[c])
+		}
+		return func() []T {
+			r := rng.Float64() * total
+			cell := keys[len(keys)-1]
+			for _, c := range keys {
+				r -= 10 / float64(counts[c])
+				if r <= 0 {
+					cell = c
+					break
+				}
+			}
+			n := rng.Intn(counts[cell])
+			for i, c := range cells {
+				if c == cell {
+					if n == 0 {
+						return m.archive[i].Position
+					}
+					n--
+				}
+			}
+			return m.archive[0].Position
+		}
+	}
+	d := crowding(m.archive)
+	return func() []T {
+		a, b := rng.Intn(len(m.archive)), rng.Intn(len(m.archive))
+		if d[b] > d[a] {
+			a = b
+		}
+		return m.archive[a].Position
+	}
+}
+
+//grid puts every archive member into a hypercube of the adaptive grid. It returns the cell of each member and how many members are in each cell.
+func (m *MultiSwarm[T]) grid() (cells []int, counts map[int]int) {
+	lo := make([]float64, m.objectives)
+	hi := make([]float64, m.objectives)
+	for j := range lo {
+		lo[j], hi[j] = math.Inf(1), math.Inf(-1)
+		for _, a := range m.archive {
+			lo[j] = math.Min(lo[j], float64(a.Objectives[j]))
+			hi[j] = math.Max(hi[j], float64(a.Objectives[j]))
+		}
+	}
+	cells = make([]int, len(m.archive))
+	counts = make(map[int]int)
+	for i, a := range m.archive {
+		c := 0
+		for j := range lo {
+			d := 0
+			if hi[j] > lo[j] {
+				d = int(float64(m.divisions) * (float64(a.Objectives[j]) - lo[j]) / (hi[j] - lo[j]))
+				if d >= m.divisions {
+					d = m.divisions - 1
+				}
+			}
+			c = c*m.divisions + d
+		}
+		cells[i] = c
+		counts[c]++
+	}
+	return cells, counts
+}
+
+//crowding returns the crowding distance of each point.  Points on the ends of the front get +Inf.
+func crowding[T Float](points []ParetoPoint[T]) []float64 {
+	d := make([]float64, len(points))
+	if len(points) == 0 {
+		return d
+	}
+	idx := make([]int, len(points))
+	for j := range points[0].Objectives {
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.Slice(idx, func(a, b int) bool {
+			return points[idx[a]].Objectives[j] < points[idx[b]].Objectives[j]
+		})
+		lo := float64(points[idx[0]].Objectives[j])
+		hi := float64(points[idx[len(idx)-1]].Objectives[j])
+		d[idx[0]] = math.Inf(1)
+		d[idx[len(idx)-1]] = math.Inf(1)
+		if hi == lo {
+			continue
+		}
+		for i := 1; i < len(idx)-1; i++ {
+			d[idx[i]] += (float64(points[idx[i+1]].Objectives[j]) - float64(points[idx[i-1]].Objectives[j])) / (hi - lo)
+		}
+	}
+	return d
+}
@@ -0,0 +1,166 @@
+package pso
+
+import (
+	"math"
+	"testing"
+)
+
+//newmultiswarm returns a MultiSwarm with 4 particles in 2 dims and 2 objectives.
+func newmultiswarm(t *testing.T, seed int) *MultiSwarm[float64] {
+	s := CreateSwarm64(seed)
+	err := s.SetConstantInertia(4, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := CreateMultiSwarm(s, 2, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+//TestMultiSwarmEmptyArchive checks that a MultiSwarm whose archive is still empty after an update moves its particles
+//instead of panicking.
+func TestMultiSwarmEmptyArchive(t *testing.T) {
+	var p Pruning
+	nan := math.NaN()
+	for _, pruning := range []Pruning{p.CrowdingDistance(), p.AdaptiveGrid()} {
+		m := newmultiswarm(t, 2)
+		m.SetPruning(pruning, 5)
+		err := m.SyncUpdate([][]float64{{nan, 1}, {1, nan}, {nan, nan}, {nan, 0}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if front := m.ParetoFront(); len(front) != 0 {
+			t.Fatalf("archive has %d points, want 0", len(front))
+		}
+		err = m.SyncUpdate([][]float64{{1, 2}, {2, 1}, {nan, 0}, {3, 3}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if front := m.ParetoFront(); len(front) != 2 {
+			t.Fatalf("archive has %d points, want 2", len(front))
+		}
+	}
+}
+
+//TestMultiSwarmResetPersonalBest checks that a particle reset by the Reset Failure takes the next objectives it is given
+//as its personal best, even if they are dominated by the ones from before the reset.
+func TestMultiSwarmResetPersonalBest(t *testing.T) {
+	m := newmultiswarm(t, 3)
+	var f Failure
+	err := m.s.SetFailurePolicy(f.Reset(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.SyncUpdate([][]float64{{1, 1}, {2, 2}, {2, 2}, {2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.SyncUpdate([][]float64{{math.NaN(), 0}, {2, 2}, {2, 2}, {2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	position := m.ParticlePosition(0)
+	err = m.SyncUpdate([][]float64{{5, 5}, {2, 2}, {2, 2}, {2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(m.pbest[0], []float64{5, 5}) || !equal(m.s.particles[0].indvbest, position) {
+		t.Fatalf("personal best is %v at %v, want [5 5] at %v", m.pbest[0], m.s.particles[0].indvbest, position)
+	}
+}
+
+//TestArchive checks that the archive keeps only the non dominated points at the positions they were found at.
+func TestArchive(t *testing.T) {
+	m := newmultiswarm(t, 1)
+	err := m.SyncUpdate([][]float64{{1, 3}, {2, 2}, {3, 3}, {2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkfront(t, m.ParetoFront(), [][]float64{{1, 3}, {2, 2}})
+	position := append([]float64(nil), m.ParticlePosition(1)...)
+	err = m.SyncUpdate([][]float64{{0, 4}, {1, 1}, {5, 5}, {5, 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	front := m.ParetoFront()
+	checkfront(t, front, [][]float64{{0, 4}, {1, 1}})
+	if !equal(front[1].Position, position) {
+		t.Fatalf("point (1,1) is at %v, want %v where it was evaluated", front[1].Position, position)
+	}
+}
+
+//TestArchivePruning checks that both Prunings keep the archive at its max size, and that CrowdingDistance keeps the ends of the front.
+func TestArchivePruning(t *testing.T) {
+	var p Pruning
+	for _, pruning := range []Pruning{p.CrowdingDistance(), p.AdaptiveGrid()} {
+		m := newmultiswarm(t, 1)
+		m.archivesize = 5
+		m.SetPruning(pruning, 4)
+		for i := 0; i <= 10; i++ {
+			f := float64(i) / 10
+			m.add([]float64{f, 0}, []float64{f, 1 - f})
+		}
+		front := m.ParetoFront()
+		if len(front) != 5 {
+			t.Fatalf("pruning %d: archive has %d points, want 5", pruning, len(front))
+		}
+		for i := range front {
+			for j := range front {
+				if dominates(front[i].Objectives, front[j].Objectives) {
+					t.Fatalf("pruning %d: %v dominates %v in the archive", pruning, front[i].Objectives, front[j].Objectives)
+				}
+			}
+		}
+		if pruning == p.CrowdingDistance() && (front[0].Objectives[0] != 0 || front[4].Objectives[0] != 1) {
+			t.Fatalf("crowding distance pruned an end of the front: %v to %v", front[0].Objectives, front[4].Objectives)
+		}
+	}
+}
+
+//TestMultiSwarmOptimize checks that Schaffer's problem ends with a front inside of its Pareto set [0,2].
+func TestMultiSwarmOptimize(t *testing.T) {
+	var p Pruning
+	for _, pruning := range []Pruning{p.CrowdingDistance(), p.AdaptiveGrid()} {
+		s := CreateSwarm64(1)
+		err := s.SetConstantInertia(20, 1, 1.49445, 1.49445, 1, -10, 10, .7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := CreateMultiSwarm(s, 2, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.SetPruning(pruning, 5)
+		front, err := m.Optimize(func(x []float64) []float64 { return []float64{x[0] * x[0], (x[0] - 2) * (x[0] - 2)} }, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(front) < 10 {
+			t.Fatalf("pruning %d: front has %d points, want at least 10", pruning, len(front))
+		}
+		for _, point := range front {
+			if x := point.Position[0]; x < 0 || x > 2 {
+				t.Fatalf("pruning %d: front has a point at %v", pruning, x)
+			}
+		}
+	}
+}
+
+//checkfront fails t if the objectives of front aren't want.
+func checkfront(t *testing.T, front []ParetoPoint[float64], want [][]float64) {
+	t.Helper()
+	got := make([][]float64, len(front))
+	for i := range front {
+		got[i] = front[i].Objectives
+	}
+	if len(got) != len(want) {
+		t.Fatalf("front is %v, want %v", got, want)
+	}
+	for i := range want {
+		if !equal(got[i], want[i]) {
+			t.Fatalf("front is %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package pso
+
+import (
+	"errors"
+	"time"
+)
+
+//Budget holds the stopping conditions for Swarm.Optimize.
+//
+//A limit that is zero (or UseTarget being false) is ignored.  At least one of MaxIterations, MaxEvaluations and MaxTime
+//needs to be set, because a Target might never be reached.
+type Budget[T Float] struct {
+	MaxIterations  int
+	MaxEvaluations int
+	Target         T
+	UseTarget      bool
+	MaxTime        time.Duration
+}
+
+//Result is what is returned from Swarm.Optimize
+type Result[T Float] struct {
+	Position    []T
+	Fitness     T
+	Iterations  int
+	Evaluations int
+	Stop        StopReason
+}
+
+//Minimize sets the swarm to minimize fitness and then runs Optimize.
+func (s *Swarm[T]) Minimize(objective func([]T) T, budget Budget[T]) (Result[T], error) {
+	s.SetFitness(false)
+	return s.Optimize(objective, budget)
+}
+
+//Maximize sets the swarm to maximize fitness and then runs Optimize.
+func (s *Swarm[T]) Maximize(objective func([]T) T, budget Budget[T]) (Result[T], error) {
+	s.SetFitness(true)
+	return s.Optimize(objective, budget)
+}
+
+//Optimize evaluates objective on every particle and passes the fitnesses to SyncUpdate until a limit in budget is hit.
+//It uses whatever was set with SetFitness and whatever mode the swarm was set to.
+//
+//Evaluations are done a whole iteration at a time.  If another iteration would go over MaxEvaluations the run is stopped,
+//so an error is returned if MaxEvaluations is less than the number of particles.
+//The slice passed to objective is a copy of the particle position. It is safe to change it.
+//NaN and ±Inf fitnesses are handled with the Failure set with SetFailurePolicy.
+//If a particle is moved by something else during an iteration the run stops with ErrStaleTicket.
+func (s *Swarm[T]) Optimize(objective func([]T) T, budget Budget[T]) (Result[T], error) {
+	return s.optimize(budget, func(records []Record[T]) ([][]T, error) {
+		positions := s.positions()
+		if len(positions) != len(records) {
+			return nil, errors.New("Number of particles changed during Optimize")
+		}
+		for i := range records {
+			records[i] = Record[T]{Fitness: objective(append([]T(nil), positions[i]...))}
+		}
+		return positions, nil
+	})
+}
+
+//optimize runs the swarm until a limit in budget is hit.  evaluate fills in the record of every particle each iteration
+//and returns the positions they were evaluated at.
+func (s *Swarm[T]) optimize(budget Budget[T], evaluate func(records []Record[T]) ([][]T, error)) (Result[T], error) {
+	var r Result[T]
+	if budget.MaxIterations <= 0 && budget.MaxEvaluations <= 0 && budget.MaxTime <= 0 {
+		return r, errors.New("Budget needs MaxIterations, MaxEvaluations or MaxTime set")
+	}
+	n := s.NumOfParticles()
+	if n == 0 {
+		return r, errors.New("Swarm has no particles")
+	}
+	if budget.MaxEvaluations > 0 && budget.MaxEvaluations < n {
+		return r, errors.New("MaxEvaluations is less than the number of particles")
+	}
+	start := time.Now()
+	records := make([]Record[T], n)
+	var err error
+	for {
+		if budget.MaxIterations > 0 && r.Iterations >= budget.MaxIterations {
+			r.Stop.MaxIterations()
+			break
+		}
+		if budget.MaxEvaluations > 0 && r.Evaluations+len(records) > budget.MaxEvaluations {
+			r.Stop.MaxEvaluations()
+			break
+		}
+		if budget.MaxTime > 0 && time.Since(start) >= budget.MaxTime {
+			r.Stop.MaxTime()
+			break
+		}
+		var positions [][]T
+		positions, err = evaluate(records)
+		if err != nil {
+			break
+		}
+		r.Evaluations += len(records)
+		err = s.syncupdate(records, positions, false)
+		if err != nil {
+			break
+		}
+		r.Iterations++
+		if budget.UseTarget && s.reached(budget.Target) {
+			r.Stop.Target()
+			break
+		}
+	}
+	r.Fitness = s.GlobalFitness()
+	r.Position = s.GlobalPosition()
+	return r, err
+}
+
+func (s *Swarm[T]) reached(target T) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if !s.hasbest {
+		return false
+	}
+	if s.max {
+		return s.fitness >= target
+	}
+	return s.fitness <= target
+}
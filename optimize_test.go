@@ -0,0 +1,35 @@
+package pso
+
+import "testing"
+
+//TestBudget checks the budgets Optimize refuses and that it stops at the limit that is hit first.
+func TestBudget(t *testing.T) {
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(10, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sphere := func(x []float64) float64 { return x[0]*x[0] + x[1]*x[1] }
+	for _, b := range []Budget64{{}, {Target: 1e-300, UseTarget: true}, {MaxEvaluations: 9}} {
+		_, err = s.Minimize(sphere, b)
+		if err == nil {
+			t.Fatalf("Minimize with budget %+v returned no error", b)
+		}
+	}
+	r, err := s.Minimize(sphere, Budget64{MaxEvaluations: 25})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stop StopReason
+	if r.Evaluations != 20 || r.Iterations != 2 || r.Stop != stop.MaxEvaluations() || len(r.Position) != 2 {
+		t.Fatalf("got %d evaluations, %d iterations, stop %v and a %d dim position, want 20, 2, MaxEvaluations and 2",
+			r.Evaluations, r.Iterations, r.Stop, len(r.Position))
+	}
+	r, err = s.Minimize(sphere, Budget64{MaxIterations: 1000, Target: 1, UseTarget: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Stop != stop.Target() || r.Fitness > 1 || r.Iterations >= 1000 {
+		t.Fatalf("stopped with %v at %v after %d iterations, want Target at 1 or less", r.Stop, r.Fitness, r.Iterations)
+	}
+}
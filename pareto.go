@@ -0,0 +1,389 @@
+package pso
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+//Objectives returns the objectives of every point in front.  The indicators take fronts this way so reference fronts
+//from files or known optimums can be passed the same way.
+func Objectives[T Float](front []ParetoPoint[T]) [][]T {
+	o := make([][]T, len(front))
+	for i := range front {
+		o[i] = front[i].Objectives
+	}
+	return o
+}
+
+//Hypervolume returns the volume of objective space dominated by front and bounded by reference.  Bigger is better.
+//Points that don't dominate reference add nothing.
+//
+//It is exact for up to 3 objectives.  Past that it is estimated by Monte Carlo with samples points drawn with seed.
+func Hypervolume[T Float](front [][]T, reference []T, samples int, seed int64) (float64, error) {
+	m := len(reference)
+	if m == 0 {
+		return 0, errors.New("Reference point is empty")
+	}
+	points := make([][]float64, 0, len(front))
+	for _, f := range front {
+		if len(f) != m {
+			return 0, errors.New("Front and reference point not the same number of objectives")
+		}
+		inside := true
+		p := make([]float64, m)
+		for j := range f {
+			p[j] = float64(f[j])
+			if p[j] >= float64(reference[j]) {
+				inside = false
+			}
+		}
+		if inside {
+			points = append(points, p)
+		}
+	}
+	ref := make([]float64, m)
+	for j := range ref {
+		ref[j] = float64(reference[j])
+	}
+	if len(points) == 0 {
+		return 0, nil
+	}
+	switch m {
+	case 1:
+		lo := ref[0]
+		for _, p := range points {
+			lo = math.Min(lo, p[0])
+		}
+		return ref[0] - lo, nil
+	case 2:
+		return hv2(points, ref[0], ref[1]), nil
+	case 3:
+		return hv3(points, ref), nil
+	}
+	if samples < 1 {
+		return 0, errors.New("Need at least one sample past 3 objectives")
+	}
+	ideal := make([]float64, m)
+	copy(ideal, ref)
+	for _, p := range points {
+		for j := range p {
+			ideal[j] = math.Min(ideal[j], p[j])
+		}
+	}
+	box := 1.0
+	for j := range ideal {
+		box *= ref[j] - ideal[j]
+	}
+	rng := rand.New(rand.NewSource(seed))
+	x := make([]float64, m)
+	hits := 0
+	for n := 0; n < samples; n++ {
+		for j := range x {
+			x[j] = ideal[j] + rng.Float64()*(ref[j]-ideal[j])
+		}
+		for _, p := range points {
+			if weaklydominates(p, x) {
+				hits++
+				break
+			}
+		}
+	}
+	return box * float64(hits) / float64(samples), nil
+}
+
+//hv2 is the exact 2 objective hypervolume. It sweeps the points by the first objective.
+func hv2(points [][]float64, r0, r1 float64) float64 {
+	sorted := append([][]float64(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i][0] == sorted[j][0] {
+			return sorted[i][1] < sorted[j][1]
+		}
+		return sorted[i][0] < sorted[j][0]
+	})
+	var hv float64
+	top := r1
+	for _, p := range sorted {
+		if p[1] < top {
+			hv += (r0 - p[0]) * (top - p[1])
+			top = p[1]
+		}
+	}
+	return hv
+}
+
+//hv3 is the exact 3 objective hypervolume. It slices along the third objective and adds up the 2 objective areas.
+func hv3(points [][]float64, ref []float64) float64 {
+	sorted := append([][]float64(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][2] < sorted[j][2] })
+	var hv float64
+	for i := range sorted {
+		next := ref[2]
+		if i+1 < len(sorted) {
+			next = sorted[i+1][2]
+		}
+		if next > sorted[i][2] {
+			hv += hv2(sorted[:i+1], ref[0], ref[1]) * (next - sorted[i][2])
+		}
+	}
+	return hv
+}
+
+func weaklydominates(a, b []float64) bool {
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//IGD returns the inverted generational distance of front.  It is the mean distance from each point of reference
+//to the closest point of front.  Smaller is better.
+func IGD[T Float](front, reference [][]T) (float64, error) {
+	return igd(front, reference, func(a, z []T) float64 {
+		var d float64
+		for i := range a {
+			d += (float64(a[i]) - float64(z[i])) * (float64(a[i]) - float64(z[i]))
+		}
+		return math.Sqrt(d)
+	})
+}
+
+//IGDPlus returns IGD+ of front.  It is IGD but only counts how much a point of front is worse than a reference point,
+//which makes it Pareto compliant.  Smaller is better.
+func IGDPlus[T Float](front, reference [][]T) (float64, error) {
+	return igd(front, reference, func(a, z []T) float64 {
+		var d float64
+		for i := range a {
+			w := math.Max(float64(a[i])-float64(z[i]), 0)
+			d += w * w
+		}
+		return math.Sqrt(d)
+	})
+}
+
+func igd[T Float](front, reference [][]T, distance func(a, z []T) float64) (float64, error) {
+	if len(front) == 0 || len(reference) == 0 {
+		return 0, errors.New("Front and reference need at least one point")
+	}
+	err := samelength(front, reference)
+	if err != nil {
+		return 0, err
+	}
+	var sum float64
+	for _, z := range reference {
+		min := math.Inf(1)
+		for _, a := range front {
+			min = math.Min(min, distance(a, z))
+		}
+		sum += min
+	}
+	return sum / float64(len(reference)), nil
+}
+
+//Spacing returns Schott's spacing of front.  It is the standard deviation of the Manhattan distance from each point
+//to its closest neighbor.  Zero means the points are evenly spaced.
+func Spacing[T Float](front [][]T) (float64, error) {
+	if len(front) < 2 {
+		return 0, errors.New("Front needs at least two points")
+	}
+	err := samelength(front, nil)
+	if err != nil {
+		return 0, err
+	}
+	d := nearest(front, func(a, b []T) float64 {
+		var d float64
+		for k := range a {
+			d += math.Abs(float64(a[k]) - float64(b[k]))
+		}
+		return d
+	})
+	var mean float64
+	for _, di := range d {
+		mean += di / float64(len(d))
+	}
+	var s float64
+	for _, di := range d {
+		s += (mean - di) * (mean - di)
+	}
+	return math.Sqrt(s / float64(len(d)-1)), nil
+}
+
+//Spread returns the generalized spread (Delta) of front.  It measures how evenly front covers reference, including
+//how close it reaches to the extreme points of reference.  Zero is ideal.
+func Spread[T Float](front, reference [][]T) (float64, error) {
+	if len(front) < 2 || len(reference) == 0 {
+		return 0, errors.New("Front needs at least two points and reference at least one")
+	}
+	err := samelength(front, reference)
+	if err != nil {
+		return 0, err
+	}
+	euclid := func(a, b []T) float64 {
+		var d float64
+		for k := range a {
+			d += (float64(a[k]) - float64(b[k])) * (float64(a[k]) - float64(b[k]))
+		}
+		return math.Sqrt(d)
+	}
+	var extremes float64
+	for j := range reference[0] {
+		e := reference[0]
+		for _, z := range reference {
+			if z[j] > e[j] {
+				e = z
+			}
+		}
+		min := math.Inf(1)
+		for _, a := range front {
+			min = math.Min(min, euclid(a, e))
+		}
+		extremes += min
+	}
+	d := nearest(front, euclid)
+	var mean float64
+	for _, di := range d {
+		mean += di / float64(len(d))
+	}
+	var sum float64
+	for _, di := range d {
+		sum += math.Abs(di - mean)
+	}
+	denom := extremes + float64(len(d))*mean
+	if denom == 0 {
+		return 0, nil
+	}
+	return (extremes + sum) / denom, nil
+}
+
+//nearest returns the distance from each point to its closest neighbor.
+func nearest[T Float](front [][]T, distance func(a, b []T) float64) []float64 {
+	d := make([]float64, len(front))
+	for i := range front {
+		d[i] = math.Inf(1)
+		for j := range front {
+			if i != j {
+				d[i] = math.Min(d[i], distance(front[i], front[j]))
+			}
+		}
+	}
+	return d
+}
+
+//samelength returns an error if the points of a and b don't all have the same number of objectives.
+func samelength[T Float](a, b [][]T) error {
+	var m int
+	if len(a) > 0 {
+		m = len(a[0])
+	}
+	for _, set := range [2][][]T{a, b} {
+		for _, p := range set {
+			if len(p) != m {
+				return errors.New("Points not the same number of objectives")
+			}
+		}
+	}
+	return nil
+}
+
+//WriteFrontCSV writes front to w as CSV.  The header is x0..xn then f0..fm.
+func WriteFrontCSV[T Float](w io.Writer, front []ParetoPoint[T]) error {
+	cw := csv.NewWriter(w)
+	if len(front) > 0 {
+		header := make([]string, 0, len(front[0].Position)+len(front[0].Objectives))
+		for i := range front[0].Position {
+			header = append(header, "x"+strconv.Itoa(i))
+		}
+		for i := range front[0].Objectives {
+			header = append(header, "f"+strconv.Itoa(i))
+		}
+		cw.Write(header)
+	}
+	bits := precision[T]()
+	for _, p := range front {
+		record := make([]string, 0, len(p.Position)+len(p.Objectives))
+		for _, x := range p.Position {
+			record = append(record, strconv.FormatFloat(float64(x), 'g', -1, bits))
+		}
+		for _, f := range p.Objectives {
+			record = append(record, strconv.FormatFloat(float64(f), 'g', -1, bits))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+//WriteFrontJSON writes front to w as a JSON array of objects with Position and Objectives.
+func WriteFrontJSON[T Float](w io.Writer, front []ParetoPoint[T]) error {
+	type point struct {
+		Position   []number
+		Objectives []number
+	}
+	points := make([]point, len(front))
+	for i, p := range front {
+		points[i] = point{Position: tonumbers(p.Position), Objectives: tonumbers(p.Objectives)}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(points)
+}
+
+//WriteFrontSVG writes a width by height SVG scatter plot of a 2 objective front to w.
+func WriteFrontSVG[T Float](w io.Writer, front []ParetoPoint[T], width, height int) error {
+	const margin = 50
+	if width <= 2*margin || height <= 2*margin {
+		return errors.New("Plot needs to be bigger than 100x100")
+	}
+	lo := [2]float64{math.Inf(1), math.Inf(1)}
+	hi := [2]float64{math.Inf(-1), math.Inf(-1)}
+	for _, p := range front {
+		if len(p.Objectives) != 2 {
+			return errors.New("SVG plot needs 2 objectives")
+		}
+		for j := range lo {
+			lo[j] = math.Min(lo[j], float64(p.Objectives[j]))
+			hi[j] = math.Max(hi[j], float64(p.Objectives[j]))
+		}
+	}
+	pw, ph := float64(width-2*margin), float64(height-2*margin)
+	scale := func(v float64, j int, size float64) float64 {
+		if hi[j] == lo[j] {
+			return size / 2
+		}
+		return (v - lo[j]) / (hi[j] - lo[j]) * size
+	}
+	var err error
+	printf := func(format string, a ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, a...)
+		}
+	}
+	printf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	printf("<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+	printf("<rect x=\"%d\" y=\"%d\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"black\"/>\n", margin, margin, pw, ph)
+	if len(front) > 0 {
+		printf("<g font-family=\"sans-serif\" font-size=\"12\">\n")
+		printf("<text x=\"%d\" y=\"%d\">%.4g</text>\n", margin, height-margin+15, lo[0])
+		printf("<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%.4g</text>\n", width-margin, height-margin+15, hi[0])
+		printf("<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%.4g</text>\n", margin-5, height-margin, lo[1])
+		printf("<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%.4g</text>\n", margin-5, margin+10, hi[1])
+		printf("<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">f0</text>\n", width/2, height-margin+30)
+		printf("<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">f1</text>\n", margin-30, height/2)
+		printf("</g>\n")
+	}
+	for _, p := range front {
+		x := float64(margin) + scale(float64(p.Objectives[0]), 0, pw)
+		y := float64(height-margin) - scale(float64(p.Objectives[1]), 1, ph)
+		printf("<circle cx=\"%.2f\" cy=\"%.2f\" r=\"3\" fill=\"steelblue\"/>\n", x, y)
+	}
+	printf("</svg>\n")
+	return err
+}
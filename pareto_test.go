@@ -0,0 +1,123 @@
+package pso
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+//TestIndicators checks the front indicators against values worked out by hand.
+func TestIndicators(t *testing.T) {
+	hv := func(front [][]float64, reference []float64) float64 {
+		v, err := Hypervolume(front, reference, 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for _, c := range []struct {
+		name string
+		got  func() (float64, error)
+		want float64
+	}{
+		{"Hypervolume 1", func() (float64, error) { return hv([][]float64{{2}, {1}}, []float64{5}), nil }, 4},
+		{"Hypervolume 2", func() (float64, error) { return hv([][]float64{{1, 2}, {2, 1}}, []float64{3, 3}), nil }, 3},
+		{"Hypervolume 2 outside", func() (float64, error) { return hv([][]float64{{1, 2}, {4, 0}}, []float64{3, 3}), nil }, 2},
+		{"Hypervolume 3", func() (float64, error) { return hv([][]float64{{0, 0, 1}, {1, 1, 0}}, []float64{2, 2, 2}), nil }, 5},
+		{"IGD", func() (float64, error) { return IGD([][]float64{{0, 0}}, [][]float64{{3, 4}, {0, 0}}) }, 2.5},
+		{"IGD diagonal", func() (float64, error) { return IGD([][]float64{{1, 1}}, [][]float64{{0, 2}, {2, 0}}) }, math.Sqrt2},
+		{"IGDPlus", func() (float64, error) { return IGDPlus([][]float64{{1, 1}}, [][]float64{{0, 2}, {2, 0}}) }, 1},
+		{"Spacing even", func() (float64, error) { return Spacing([][]float64{{0, 2}, {1, 1}, {2, 0}}) }, 0},
+		{"Spacing", func() (float64, error) { return Spacing([][]float64{{0, 3}, {1, 2}, {3, 0}}) }, math.Sqrt(4.0 / 3)},
+		{"Spread", func() (float64, error) {
+			front := [][]float64{{0, 2}, {1, 1}, {2, 0}}
+			return Spread(front, front)
+		}, 0},
+	} {
+		got, err := c.got()
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if math.Abs(got-c.want) > 1e-12 {
+			t.Fatalf("%s is %v, want %v", c.name, got, c.want)
+		}
+	}
+	if _, err := Hypervolume([][]float64{{1, 2}}, []float64{3}, 0, 1); err == nil {
+		t.Fatal("Hypervolume with a reference of the wrong length returned no error")
+	}
+	if _, err := Spacing([][]float64{{1, 2}}); err == nil {
+		t.Fatal("Spacing of one point returned no error")
+	}
+}
+
+//TestHypervolume3MonteCarlo checks the exact 3 objective hypervolume against the Monte Carlo estimate of the same
+//front with a fourth objective that is 0 for every point and 1 at the reference.
+func TestHypervolume3MonteCarlo(t *testing.T) {
+	front := [][]float64{{.1, .8, .5}, {.5, .2, .7}, {.8, .5, .1}, {.3, .3, .9}, {.6, .6, .4}, {.9, .1, .6}}
+	reference := []float64{1, 1, 1}
+	exact, err := Hypervolume(front, reference, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	padded := make([][]float64, len(front))
+	for i := range front {
+		padded[i] = append(append([]float64(nil), front[i]...), 0)
+	}
+	estimate, err := Hypervolume(padded, append(append([]float64(nil), reference...), 1), 400000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(exact-estimate) > .005 {
+		t.Fatalf("exact hypervolume is %v, Monte Carlo estimate %v", exact, estimate)
+	}
+}
+
+//TestWriteFront checks the CSV, JSON and SVG output of a front.
+func TestWriteFront(t *testing.T) {
+	front := []ParetoPoint[float64]{
+		{Position: []float64{1, 2}, Objectives: []float64{3, 4.5}},
+		{Position: []float64{-1, .25}, Objectives: []float64{5, math.Inf(1)}},
+	}
+	var buf bytes.Buffer
+	err := WriteFrontCSV(&buf, front)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "x0,x1,f0,f1\n1,2,3,4.5\n-1,0.25,5,+Inf\n"; buf.String() != want {
+		t.Fatalf("CSV is %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	err = WriteFrontJSON(&buf, front)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var points []struct{ Position, Objectives []number }
+	err = json.Unmarshal(buf.Bytes(), &points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0].Objectives[1] != 4.5 || !math.IsInf(float64(points[1].Objectives[1]), 1) || points[1].Position[1] != .25 {
+		t.Fatalf("JSON decoded to %+v", points)
+	}
+
+	buf.Reset()
+	front[1].Objectives[1] = 1
+	err = WriteFrontSVG(&buf, front, 400, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") || strings.Count(svg, "<circle ") != 2 {
+		t.Fatalf("SVG isn't a plot of 2 points:\n%s", svg)
+	}
+	if err = WriteFrontSVG(&buf, front, 100, 300); err == nil {
+		t.Fatal("WriteFrontSVG with a 100 wide plot returned no error")
+	}
+	front[0].Objectives = append(front[0].Objectives, 1)
+	if err = WriteFrontSVG(&buf, front, 400, 300); err == nil {
+		t.Fatal("WriteFrontSVG with 3 objectives returned no error")
+	}
+}
@@ -0,0 +1,146 @@
+package pso
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+//Float is the constraint for the precisions a swarm can run at.
+type Float interface {
+	float32 | float64
+}
+
+type particle[T Float] struct {
+	mux       *sync.Mutex
+	rng       *rand.Rand
+	source    *source
+	hasbest   bool
+	fitness   T
+	violation T
+	keys      []T
+	retries   int
+	position  []T
+	indvbest  []T
+	velocity  []T
+	inertia   T
+	alpha     T
+	vmax      T
+}
+
+func createparticle[T Float](maxv T, minxstart, maxxstart []T, maxalpha, maxinertia T, dims int, seed int64) particle[T] {
+	source := newsource(seed)
+	rng := rand.New(source)
+	position := make([]T, dims)
+	indvbest := make([]T, dims)
+	velocity := make([]T, dims)
+	var val T
+	for i := range position {
+		val = ((maxxstart[i] - minxstart[i]) * randf[T](rng)) + minxstart[i]
+		position[i] = val
+		indvbest[i] = val
+		velocity[i] = randf[T](rng) * maxv
+	}
+	return particle[T]{
+		mux:       new(sync.Mutex),
+		rng:       rng,
+		source:    source,
+		violation: T(math.Inf(1)),
+		position:  position,
+		indvbest:  indvbest,
+		velocity:  velocity,
+		inertia:   randf[T](rng) * maxinertia,
+		alpha:     randf[T](rng) * maxalpha,
+	}
+}
+
+//isbest sets the personal best to position if there is no personal best yet or r is better than it.  position is the position r was evaluated at.
+func (p *particle[T]) isbest(r Record[T], position []T, better func(a, b Record[T]) bool) {
+	if !p.hasbest || better(r, p.record()) {
+		p.hasbest = true
+		p.fitness = r.Fitness
+		p.violation = r.Violation
+		p.keys = append(p.keys[:0:0], r.Keys...)
+		copy(p.indvbest, position)
+	}
+}
+
+//copyposition returns a copy of the position.  It locks the particle because IndvSyncUpdatePart3 moves particles under the swarm's read lock.
+func (p *particle[T]) copyposition() []T {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return append([]T(nil), p.position...)
+}
+
+//record returns the record of the personal best.
+func (p *particle[T]) record() Record[T] {
+	return Record[T]{Fitness: p.fitness, Violation: p.violation, Keys: p.keys}
+}
+
+func (p *particle[T]) reset(maxv T, minxstart, maxxstart []T, maxalpha, maxinertia T) {
+	var val T
+	for i := range p.position {
+		val = ((maxxstart[i] - minxstart[i]) * randf[T](p.rng)) + minxstart[i]
+		p.position[i] = val
+		p.indvbest[i] = val
+		p.velocity[i] = randf[T](p.rng) * maxv
+
+	}
+	p.hasbest = false
+	p.fitness = 0
+	p.violation = T(math.Inf(1))
+	p.keys = nil
+	p.retries = 0
+	p.alpha = randf[T](p.rng) * maxalpha
+	p.inertia = randf[T](p.rng) * maxinertia
+}
+
+//Update will update velocities,and position with u. Then puts the particle back inside lower and upper if they are set.
+func (p *particle[T]) update(u VelocityUpdater[T], cognative, social, vmax, constriction T, informantbest, lower, upper []T, b Boundary, k int) {
+	if u != nil {
+		state := ParticleState[T]{
+			Position:      p.position,
+			Velocity:      p.velocity,
+			PersonalBest:  p.indvbest,
+			InformantBest: informantbest,
+			Inertia:       p.inertia,
+			Alpha:         p.alpha,
+			Cognative:     cognative,
+			Social:        social,
+			Vmax:          vmax,
+			Constriction:  constriction,
+			Rng:           p.rng,
+			Iteration:     k,
+		}
+		u.Update(&state)
+		p.inertia = state.Inertia
+		p.alpha = state.Alpha
+	}
+	p.bound(lower, upper, b)
+
+}
+
+func minmagnitude[T Float](v, vmax T) T {
+
+	if v < 0 {
+		if vmax < (-v) {
+			return -vmax
+		}
+		return v
+
+	}
+	if vmax < v {
+		return vmax
+	}
+	return v
+
+}
+
+//randf returns a random number in [0,1) at the precision of T.
+func randf[T Float](rng *rand.Rand) T {
+	var t T
+	if _, ok := any(t).(float32); ok {
+		return T(rng.Float32())
+	}
+	return T(rng.Float64())
+}
@@ -0,0 +1,892 @@
+/*
+
+pso is based on the slides from Jaco F. Schutte EGM 6365 - Structural Optimization Fall 2005
+Link to slides https://www.mii.lt/zilinskas/uploads/Heuristic%20Algorithms/Lectures/Lect4/PSO2.pdf
+
+A custom particle algo can be passed with SetUpdater.
+*/
+
+package pso
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+//Swarm contains the particles and meta values. T is the precision the swarm runs at.
+//
+//Every exported method is safe for concurrent use.  Methods that change the swarm wait on each other, and getters
+//return copies so they can be used while the swarm keeps updating.  Updates that come in at the same time are done
+//one after another in the order they get the lock, so only a swarm driven from one goroutine is reproducible for a seed.
+//IndvSyncUpdatePart3 is the exception that runs at the same time for different particles.
+type Swarm[T Float] struct {
+	k                                                           int
+	max                                                         bool
+	hasbest                                                     bool
+	fitness, violation                                          T
+	keys                                                        []T
+	cognative, social, vmax, constriction, alphamax, inertiamax T
+	xminstart, xmaxstart, lower, upper                          []T
+	boundary                                                    Boundary
+	topology                                                    Topology
+	constraint                                                  ConstraintHandler[T]
+	comparator                                                  Comparator[T]
+	failure                                                     Failure
+	retries                                                     int
+	failures                                                    Failures
+	particles                                                   []particle[T]
+	globalposition                                              []T
+	mode                                                        Mode
+	transfer                                                    Transfer
+	space                                                       *SearchSpace
+	updater                                                     VelocityUpdater[T]
+	custom                                                      VelocityUpdater[T]
+	source                                                      *source
+	rng                                                         *rand.Rand
+	mux                                                         *sync.RWMutex
+}
+
+//FitnessIndex is used with reseting, killing and getting the fitnesses of particles
+type FitnessIndex[T Float] struct {
+	Particle int
+	Fitness  T
+}
+
+//CreateSwarm creates a particle swarm. If seed is negative it will initialize the rng source with computer clock.
+//If it is zero or positive it will initialize the swarm using the seed passed, and runs with the same seed
+//and the same calls will give the same results.
+//
+//Each particle inside of the swarm will get its own rng based on that seed.
+//Passing an non negative int64 from the swarm rng to each particle for its own rng.
+//There is a posiblilty of having duplicate particles.
+//Since the max value of int64 is 9,223,372,036,854,775,807 it is highly improbable.
+//
+//Since each particle uses its own rng, SyncUpdateMultiThread gives the same results as SyncUpdate.
+func CreateSwarm[T Float](seed int) *Swarm[T] {
+	src := newsource(int64(seed))
+	if seed < 0 {
+		src = newsource(time.Now().UnixNano())
+	}
+
+	return &Swarm[T]{
+		source: src,
+		rng:    rand.New(src),
+		k:      1,
+		mux:    new(sync.RWMutex),
+	}
+}
+
+//GenericSet allows you to set mode more generically.
+//
+//It returns a *ConfigError and leaves the swarm alone if the values aren't valid.  See Config.Validate.
+func (s *Swarm[T]) GenericSet(mode Mode,
+	numofparticles int,
+	dims int,
+	cognative T,
+	social T,
+	vmax T,
+	minpositionstart T,
+	maxpositionstart T,
+	alphamax T,
+	inertiamax T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.setswarm(mode, numofparticles, dims, cognative, social, vmax, minpositionstart, maxpositionstart, alphamax, inertiamax)
+}
+
+//ChangeUpdateValues will change the values are used when the swarm does it's updates.
+//
+//Ignored Values/Combinations:
+//	1)Negative numbers will be ignored.
+//	2)Social and cognative can't both be zero. That will be ignored.
+//	3)Vmax <= 0 will be ignored.
+//
+//Some values will be ignored depending on the mode.
+//
+//In Constriction mode a *ConfigError is returned and nothing is changed if cognative + social would not be > 4.
+func (s *Swarm[T]) ChangeUpdateValues(cognative, social, vmax T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	c := s.config()
+	if cognative < 0 && social >= 0 {
+		c.Social = social
+	} else if cognative >= 0 && social < 0 {
+		c.Cognative = cognative
+	} else if cognative > 0 && social > 0 {
+		c.Cognative = cognative
+		c.Social = social
+	}
+	err := c.validatemode()
+	if err != nil {
+		return err
+	}
+	s.cognative = c.Cognative
+	s.social = c.Social
+	if vmax > 0 {
+		s.vmax = vmax
+	}
+	s.constriction = constriction(s.cognative, s.social)
+	return nil
+}
+
+//ChangeMinStart will change the minstart for new or resetted particles.
+//
+//Passing one value sets the minstart of every dimension.  Passing a value for each dimension sets them per dimension.
+//
+//It returns a *ConfigError and changes nothing if the swarm isn't set up yet or a dimension wouldn't have minstart < maxstart.
+//Change maxstart first when moving the range up.
+func (s *Swarm[T]) ChangeMinStart(minstart ...T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.changestart("MinStart", s.xminstart, minstart)
+}
+
+//ChangeMaxStart will change the max start for new or resetted particles.
+//
+//Passing one value sets the maxstart of every dimension.  Passing a value for each dimension sets them per dimension.
+//
+//It returns a *ConfigError and changes nothing if the swarm isn't set up yet or a dimension wouldn't have minstart < maxstart.
+//Change minstart first when moving the range down.
+func (s *Swarm[T]) ChangeMaxStart(maxstart ...T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.changestart("MaxStart", s.xmaxstart, maxstart)
+}
+
+//changestart sets start, which is s.xminstart or s.xmaxstart, to values if every dimension still has minstart < maxstart.
+func (s *Swarm[T]) changestart(name string, start, values []T) error {
+	if len(start) == 0 {
+		return &ConfigError{name, "can't be changed before the swarm is set up"}
+	}
+	old := append([]T(nil), start...)
+	err := setstart(name, start, values)
+	if err != nil {
+		return err
+	}
+	for i := range s.xminstart {
+		if !(s.xminstart[i] < s.xmaxstart[i]) {
+			copy(start, old)
+			return &ConfigError{"MinStart", fmt.Sprintf("needs to be < MaxStart in dimension %d, got %v and %v", i, s.xminstart[i], s.xmaxstart[i])}
+		}
+	}
+	return nil
+}
+
+func setstart[T Float](name string, start, values []T) error {
+	switch len(values) {
+	case 1:
+		for i := range start {
+			start[i] = values[0]
+		}
+	case len(start):
+		copy(start, values)
+	default:
+		return &ConfigError{name, fmt.Sprintf("needs 1 or %d values, got %d", len(start), len(values))}
+	}
+	return nil
+}
+
+//ChangeAlphaMax changes alpha max for new or resetted particles.
+//
+//alphamax<=0 will be ignored
+func (s *Swarm[T]) ChangeAlphaMax(alphamax T) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if alphamax > 0 {
+		s.alphamax = alphamax
+	}
+
+}
+
+//ChangeInertiaMax changes the inertia max value for new or resetted particles
+//
+//inertiamax<=0 will be ignored
+func (s *Swarm[T]) ChangeInertiaMax(inertiamax T) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if inertiamax > 0 {
+		s.inertiamax = inertiamax
+	}
+}
+
+//ChangeMode changes the mode. Certain modes have different init values. I made the default .5. It might be too high.
+//You might want to run ChangeInitValues, first. Then run change mode, second. Then lastly run ResetParticles with a good chunk being reset.
+//
+//Changing to Custom mode keeps the VelocityUpdater that was passed to SetUpdater.
+//
+//It returns a *ConfigError and keeps the mode if mode isn't a Mode, if it is Custom and no VelocityUpdater was set,
+//if it is Constriction and cognative + social isn't > 4, or if it is Binary and the swarm isn't.  Use SetBinary for Binary mode.
+func (s *Swarm[T]) ChangeMode(mode Mode) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var m Mode
+	if mode == m.Binary() && s.mode != m.Binary() {
+		return &ConfigError{"Mode", "can only be set to Binary with SetBinary"}
+	}
+	c := s.config()
+	c.Mode = mode
+	c.Updater = s.custom
+	err := c.validatemode()
+	if err != nil {
+		return err
+	}
+	s.setmode(mode)
+	return nil
+}
+
+//SetVanilla sets the pso to vanilla mode.  It returns a *ConfigError if the values aren't valid.
+func (s *Swarm[T]) SetVanilla(
+	numofparticles int,
+	dims int,
+	cognative T,
+	social T,
+	vmax T,
+	minpositionstart T,
+	maxpositionstart T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var m Mode
+	return s.setswarm(m.Vanilla(), numofparticles, dims, cognative, social, vmax, minpositionstart, maxpositionstart, .5, .5)
+}
+
+//SetConstantInertia sets the particles update local positions based on Constant Inertia algorithm.  It returns a *ConfigError if the values aren't valid.
+func (s *Swarm[T]) SetConstantInertia(
+	numofparticles int,
+	dims int,
+	cognative T,
+	social T,
+	vmax T,
+	minpositionstart T,
+	maxpositionstart T,
+	inertiamax T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var m Mode
+	return s.setswarm(m.ConstantInertia(), numofparticles, dims, cognative, social, vmax, minpositionstart, maxpositionstart, .5, inertiamax)
+}
+
+//SetConstriction sets the particles update local positions based on Constriction algorithm.  It returns a *ConfigError if the values aren't valid.
+func (s *Swarm[T]) SetConstriction(
+	numofparticles int,
+	dims int,
+	cognative T,
+	social T,
+	vmax T,
+	minpositionstart T,
+	maxpositionstart T,
+) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var m Mode
+	return s.setswarm(m.Constriction(), numofparticles, dims, cognative, social, vmax, minpositionstart, maxpositionstart, .5, .5)
+}
+
+//SetDynamicInertiaMaxVelocityReduction sets the particals to Dynamic Inertria Max Velocity Reduction.  It returns a *ConfigError if the values aren't valid.
+func (s *Swarm[T]) SetDynamicInertiaMaxVelocityReduction(
+	numofparticles, dims int,
+	cognative, social, vmaxgamma, minpositionstart, maxpositionstart, inertiamax T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var m Mode
+	return s.setswarm(m.DynamicInertiaMaxVelReduction(), numofparticles, dims, cognative, social, vmaxgamma, minpositionstart, maxpositionstart, 1, inertiamax)
+}
+
+//SetLinearInertiaReduce sets the particles to LinearInertiaReduce.  It returns a *ConfigError if the values aren't valid.
+func (s *Swarm[T]) SetLinearInertiaReduce(
+	numofparticles int,
+	dims int,
+	cognative T,
+	social T,
+	vmax T,
+	minpositionstart T,
+	maxpositionstart T,
+	alphamax T,
+	inertiamax T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var m Mode
+	return s.setswarm(m.InertiaReduction(), numofparticles, dims, cognative, social, vmax, minpositionstart, maxpositionstart, alphamax, inertiamax)
+
+}
+
+//SetFitness sets the PSO's to either fitness.
+//
+//If max is true. It will try to maximize.
+//
+//If max is false. It will try to minimize.
+//
+//Default is false.
+//
+//This can be switched at any time.  Bests found before the switch are compared the new way.
+func (s *Swarm[T]) SetFitness(max bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.max = max
+}
+
+//setswarm sets up the swarm the way the Set methods do.  Everything they don't take is kept from the swarm's Config.
+//Bounds that don't fit dims are dropped.
+func (s *Swarm[T]) setswarm(
+	mode Mode,
+	numofparticles int,
+	dims int,
+	cognative T,
+	social T,
+	vmax T,
+	xminstart T,
+	xmaxstart T,
+	alphamax T,
+	inertiamax T) error {
+	c := s.config()
+	c.Mode = mode
+	c.Particles = numofparticles
+	c.Dims = dims
+	c.Cognative = cognative
+	c.Social = social
+	c.Vmax = vmax
+	c.MinStart = []T{xminstart}
+	c.MaxStart = []T{xmaxstart}
+	c.AlphaMax = alphamax
+	c.InertiaMax = inertiamax
+	if len(c.Lower) != dims {
+		c.Lower, c.Upper = nil, nil
+	}
+	c.Updater = s.custom
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+	s.configure(c)
+	return nil
+}
+
+//configure sets up the swarm with c.  c needs to be valid.
+func (s *Swarm[T]) configure(c Config[T]) {
+	var m Mode
+	s.particles = nil
+	s.globalposition = make([]T, c.Dims)
+	s.cognative = c.Cognative
+	s.social = c.Social
+	s.vmax = c.Vmax
+	s.xminstart = make([]T, c.Dims)
+	s.xmaxstart = make([]T, c.Dims)
+	if c.Mode == m.Binary() {
+		c.MinStart, c.MaxStart = []T{0}, []T{1}
+	}
+	setstart("MinStart", s.xminstart, c.MinStart)
+	setstart("MaxStart", s.xmaxstart, c.MaxStart)
+	if s.setbounds(c.Lower, c.Upper) != nil {
+		s.lower, s.upper = nil, nil
+	}
+	s.boundary = c.Boundary
+	if s.space != nil && s.space.Dims() != c.Dims {
+		s.space = nil
+	}
+	s.topology = fresh(c.Topology)
+	s.transfer = c.Transfer
+	if c.Mode == m.Custom() && c.Updater != nil {
+		s.custom = c.Updater
+	}
+	s.max = c.Max
+	s.particles = make([]particle[T], c.Particles)
+	s.inertiamax = c.InertiaMax
+	s.alphamax = c.AlphaMax
+	s.constriction = constriction(c.Cognative, c.Social)
+	s.clearbest()
+	s.setmode(c.Mode)
+	minstart, maxstart := s.startrange()
+	for i := range s.particles {
+		s.particles[i] = createparticle(c.Vmax, minstart, maxstart, c.AlphaMax, c.InertiaMax, c.Dims, s.rng.Int63())
+		s.startbits(&s.particles[i])
+
+	}
+
+}
+
+//move updates the velocity and position of particle i with the swarm's VelocityUpdater.
+//Dimensions that are inactive in the search space for the particle are left where they are.
+func (s *Swarm[T]) move(i int, informantbest []T) {
+	p := &s.particles[i]
+	inactive := s.inactive(p.position)
+	var kept []T
+	for _, j := range inactive {
+		kept = append(kept, p.position[j])
+	}
+	p.update(s.updater, s.cognative, s.social, s.vmax, s.constriction, informantbest, s.lower, s.upper, s.boundary, s.k)
+	for n, j := range inactive {
+		p.position[j] = kept[n]
+		p.velocity[j] = 0
+	}
+}
+
+//setmode sets the mode and the VelocityUpdater that goes with it.  Custom mode uses the VelocityUpdater passed to SetUpdater.
+func (s *Swarm[T]) setmode(mode Mode) {
+	s.mode = mode
+	var m Mode
+	switch mode {
+	case m.Custom():
+		s.updater = s.custom
+	case m.Binary():
+		s.updater = BinaryUpdater[T]{Transfer: s.transfer}
+	default:
+		s.updater = updaterfor[T](mode)
+	}
+}
+
+//ResetParticles resets the particles based on the index array passed.  Nothing is reset if an index is out of bounds.
+//If resetglobalposition is true the global best is cleared too and HasBest returns false until the next update.
+func (s *Swarm[T]) ResetParticles(indexes []FitnessIndex[T], resetglobalposition bool) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	numofparticles := len(s.particles)
+	if len(indexes) > numofparticles {
+		return errors.New("Length of indexes larger than particle number")
+	}
+	for i := range indexes {
+		if indexes[i].Particle < 0 || indexes[i].Particle >= numofparticles {
+			return errors.New("Index out of bounds")
+		}
+	}
+	if resetglobalposition {
+		s.clearbest()
+	}
+
+	minstart, maxstart := s.startrange()
+	for i := range indexes {
+		s.particles[indexes[i].Particle].reset(s.vmax, minstart, maxstart, s.alphamax, s.inertiamax)
+		s.startbits(&s.particles[indexes[i].Particle])
+	}
+
+	return nil
+}
+
+//ResetParticle resets the particles based on the index array passed
+func (s *Swarm[T]) ResetParticle(index int) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if index < 0 || index >= len(s.particles) {
+		return errors.New("Index out of bounds")
+	}
+	minstart, maxstart := s.startrange()
+	s.particles[index].reset(s.vmax, minstart, maxstart, s.alphamax, s.inertiamax)
+	s.startbits(&s.particles[index])
+
+	return nil
+}
+
+//AsyncUpdate does the update asyncrounusly
+//
+//fitness is taken to be the fitness of the particle's current position.  Use AskTell in SteadyState mode if the
+//particle could have moved since its position was evaluated.
+func (s *Swarm[T]) AsyncUpdate(index int, fitness T) error {
+	return s.asyncupdate(index, Record[T]{Fitness: fitness}, nil)
+}
+
+//AsyncUpdateConstrained is AsyncUpdate with the constraint violation of the particle's position.
+//Records are compared with the ConstraintHandler set with SetConstraintHandler.
+func (s *Swarm[T]) AsyncUpdateConstrained(index int, fitness, violation T) error {
+	return s.asyncupdate(index, Record[T]{Fitness: fitness, Violation: violation}, nil)
+}
+
+//AsyncUpdateRecord is AsyncUpdate with a whole Record, like when a Comparator uses Keys.
+func (s *Swarm[T]) AsyncUpdateRecord(index int, r Record[T]) error {
+	return s.asyncupdate(index, r, nil)
+}
+
+//asyncupdate updates particle index with r.  position is the position r was evaluated at.
+//If it is nil the particle's current position is used.  If it isn't where the particle is now ErrStaleTicket is returned.
+func (s *Swarm[T]) asyncupdate(index int, r Record[T], position []T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if index < 0 || index >= len(s.particles) {
+		return errors.New("Index Out Of Bounds")
+	}
+	if position == nil {
+		position = s.particles[index].position
+	} else if !equal(position, s.particles[index].position) {
+		return ErrStaleTicket
+	}
+	err := s.abort(index, r)
+	if err != nil {
+		return err
+	}
+	var improved, hold bool
+	if bad(r) {
+		hold = !s.fail(index, r)
+	} else {
+		s.particles[index].retries = 0
+		improved = s.isbetter(r)
+		if improved {
+			s.setbest(r, position)
+		}
+	}
+	s.k++
+	s.updatetopology(improved)
+	s.updateconstraint()
+	if hold {
+		return nil
+	}
+	if !bad(r) {
+		s.particles[index].isbest(r, position, s.better)
+	}
+	best, _ := s.informantbest(index)
+	s.move(index, best)
+	return nil
+}
+
+//HasBest returns true once the swarm has been given a fitness and has a global best.
+func (s *Swarm[T]) HasBest() bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.hasbest
+}
+
+//GlobalRecord returns a copy of the record of the global best.  It is the zero Record until HasBest is true.
+func (s *Swarm[T]) GlobalRecord() Record[T] {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	r := s.record()
+	r.Keys = append([]T(nil), r.Keys...)
+	return r
+}
+
+//GlobalFitness returns how fit the swarm is.  It is zero until HasBest is true.
+func (s *Swarm[T]) GlobalFitness() T {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.fitness
+}
+
+//GlobalPosition returns a copy of the swarm best global position.
+func (s *Swarm[T]) GlobalPosition() []T {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return append([]T(nil), s.globalposition...)
+}
+
+//ParticlePosition returns a copy of the particle position of the index passed.  It returns nil if index is out of bounds.
+func (s *Swarm[T]) ParticlePosition(index int) []T {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if index < 0 || index > len(s.particles)-1 {
+		return nil
+	}
+	return s.particles[index].copyposition()
+
+}
+
+//NumOfParticles returns the number of particles in the swarm.
+func (s *Swarm[T]) NumOfParticles() int {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return len(s.particles)
+}
+
+//ParticleFitness returns the fitness of particle at indexed location.  It returns the zero FitnessIndex if index is out of bounds.
+func (s *Swarm[T]) ParticleFitness(index int) FitnessIndex[T] {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if index < 0 || index >= len(s.particles) {
+		return FitnessIndex[T]{}
+	}
+	return FitnessIndex[T]{
+		Fitness:  s.particles[index].fitness,
+		Particle: index,
+	}
+}
+
+//KillParticles kills the partilces in the indexes slice.
+func (s *Swarm[T]) KillParticles(indexes []FitnessIndex[T]) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	numofparticles := len(s.particles)
+	if len(indexes) > numofparticles {
+		return errors.New("Length of indexes larger than particle number")
+	}
+	kill := make(map[int]bool, len(indexes))
+	for i := range indexes {
+		if indexes[i].Particle < 0 || indexes[i].Particle >= numofparticles {
+			return errors.New("Index out of bounds")
+		}
+		kill[indexes[i].Particle] = true
+	}
+	reshapedparticles := make([]particle[T], 0, numofparticles-len(kill))
+	for i := range s.particles {
+		if !kill[i] {
+			reshapedparticles = append(reshapedparticles, s.particles[i])
+		}
+	}
+	s.particles = reshapedparticles
+	return nil
+}
+
+//AddParticles addes particles to swarm from previously set conditions
+func (s *Swarm[T]) AddParticles(num int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	newparts := make([]particle[T], num)
+	minstart, maxstart := s.startrange()
+	for i := range newparts {
+		newparts[i] = createparticle(s.vmax, minstart, maxstart, s.alphamax, s.inertiamax, len(s.globalposition), s.rng.Int63())
+		s.startbits(&newparts[i])
+
+	}
+	s.particles = append(s.particles, newparts...)
+}
+
+//AllFitnesses will fill the previousfitnesses slice with values and then return it.
+//I did it this way so that if user of this package doesn't want to keep on allocating
+//memory then they can pass a already allocated slice.
+//
+//Here are the rules:
+//
+//	if previousfitnesses==nil || len(previousfitnesses)!=len(hidden particles) then
+//	method will allocate new memory and return the fitnesses of the current particles.
+//
+//They are sorted best first the same way the swarm compares fitnesses.  Particles without a personal best yet go last.
+func (s *Swarm[T]) AllFitnesses(previousfitnesses []FitnessIndex[T]) []FitnessIndex[T] {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if previousfitnesses == nil || len(previousfitnesses) != len(s.particles) {
+		previousfitnesses = make([]FitnessIndex[T], len(s.particles))
+	}
+	for i := range previousfitnesses {
+		previousfitnesses[i].Particle = i
+		previousfitnesses[i].Fitness = s.particles[i].fitness
+	}
+	sort.SliceStable(previousfitnesses, func(i, j int) bool {
+		a, b := &s.particles[previousfitnesses[i].Particle], &s.particles[previousfitnesses[j].Particle]
+		return a.hasbest && (!b.hasbest || s.better(a.record(), b.record()))
+	})
+	return previousfitnesses
+}
+
+//SyncUpdateMultiThread is a MultiThreaded sync update
+func (s *Swarm[T]) SyncUpdateMultiThread(fitnesses []T) error {
+	return s.syncupdate(records(fitnesses, nil), nil, true)
+}
+
+//SyncUpdate updates the particle swarm after all particles tested
+func (s *Swarm[T]) SyncUpdate(fitnesses []T) error {
+	return s.syncupdate(records(fitnesses, nil), nil, false)
+}
+
+//SyncUpdateConstrained is SyncUpdate with the constraint violations of the particle positions.
+//Records are compared with the ConstraintHandler set with SetConstraintHandler.
+func (s *Swarm[T]) SyncUpdateConstrained(fitnesses, violations []T) error {
+	if len(violations) != len(fitnesses) {
+		return errors.New("Sizes of losses and violations not the same")
+	}
+	return s.syncupdate(records(fitnesses, violations), nil, false)
+}
+
+//SyncUpdateRecords is SyncUpdate with a whole Record for each particle, like when a Comparator uses Keys.
+func (s *Swarm[T]) SyncUpdateRecords(records []Record[T]) error {
+	return s.syncupdate(records, nil, false)
+}
+
+//records makes records out of fitnesses and violations.  violations can be nil.
+func records[T Float](fitnesses, violations []T) []Record[T] {
+	r := make([]Record[T], len(fitnesses))
+	for i := range r {
+		r[i].Fitness = fitnesses[i]
+		if violations != nil {
+			r[i].Violation = violations[i]
+		}
+	}
+	return r
+}
+
+//syncupdate updates every particle.  positions holds the positions the records were evaluated at.
+//If it is nil the particles' current positions are used.  If a particle isn't there anymore ErrStaleTicket is returned.
+func (s *Swarm[T]) syncupdate(records []Record[T], positions [][]T, multithread bool) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if len(records) != len(s.particles) {
+		return errors.New("Sizes of losses and num of particles not the same")
+	}
+	if positions != nil {
+		if len(positions) != len(s.particles) {
+			return ErrStaleTicket
+		}
+		for i := range positions {
+			if !equal(positions[i], s.particles[i].position) {
+				return ErrStaleTicket
+			}
+		}
+	}
+	err := s.abort(0, records...)
+	if err != nil {
+		return err
+	}
+	hold := make([]bool, len(records))
+	position := -1
+	for i, r := range records {
+		if bad(r) {
+			hold[i] = !s.fail(i, r)
+			continue
+		}
+		s.particles[i].retries = 0
+		s.particles[i].isbest(r, s.particles[i].position, s.better)
+		if s.isbetter(r) {
+			s.setbest(r, s.particles[i].position)
+			position = i
+
+		}
+
+	}
+	s.updatetopology(position > -1)
+	s.updateconstraint()
+	bests := make([][]T, len(s.particles))
+	for i := range bests {
+		bests[i], _ = s.informantbest(i)
+	}
+	if multithread {
+		var wg sync.WaitGroup
+		for i := range s.particles {
+			if hold[i] {
+				continue
+			}
+			wg.Add(1)
+			go func(i int) {
+				s.move(i, bests[i])
+				wg.Done()
+			}(i)
+
+		}
+		wg.Wait()
+	} else {
+		for i := range s.particles {
+			if !hold[i] {
+				s.move(i, bests[i])
+			}
+		}
+	}
+	s.k++
+	return nil
+}
+
+//IndvSyncUpdatePart1 of 3 allows user to parallelize the syncronous update doing it in parts.
+//
+//This finds the local best for each particle.
+//There might some memory copying in this. Unless the dims are absolutly huge, or you put several of these into
+//one worker. It might be faster to not parallelize this part.
+//
+//NaN and ±Inf fitnesses are always handled like the Worst Failure by the IndvSyncUpdate parts.
+//Part1 and Part3 do nothing if particleindex is out of bounds.
+//
+//Deprecated: Use AskTell.  It keeps track of which fitness goes with which position.
+func (s *Swarm[T]) IndvSyncUpdatePart1(particleindex int, fitness T) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if particleindex < 0 || particleindex >= len(s.particles) {
+		return
+	}
+	r := Record[T]{Fitness: fitness}
+	if bad(r) {
+		s.failures.add(float64(fitness), nil)
+		s.failures.Worst++
+		return
+	}
+	s.particles[particleindex].isbest(r, s.particles[particleindex].position, s.better)
+}
+
+//IndvSyncUpdatePart2 of 3 allows user to parallelize the syncronous update doing it in parts.
+//
+//Since this sets the global best fitness and maybe sets the global best position. This part
+//isn't parallelized
+//
+//Deprecated: Use AskTell.
+func (s *Swarm[T]) IndvSyncUpdatePart2(fitnesses []T) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	position := -1
+	for i := range fitnesses {
+		if i >= len(s.particles) {
+			break
+		}
+		r := Record[T]{Fitness: fitnesses[i]}
+		if !bad(r) && s.isbetter(r) {
+			s.setbest(r, s.particles[i].position)
+			position = i
+
+		}
+
+	}
+	s.updatetopology(position > -1)
+	s.k++
+}
+
+//IndvSyncUpdatePart3 of 3 allows user to parallelize the syncronous update doing it in parts.
+//
+//Updates The Particles - Most computative part of all all 3. Most to gain from parallelism
+//
+//If globalposition is nil or not the same size as the hidden global position.  New memory will be allocated.
+//For increased speed have this preallocated. Each parallel process should get its own copy of global position.
+//
+//Part3 can run at the same time for different particles.  Calls for the same particle wait on each other.
+//
+//If a Topology is set that doesn't use the global best then globalposition is ignored and the informants best is used.
+//
+//Deprecated: Use AskTell.
+func (s *Swarm[T]) IndvSyncUpdatePart3(particleindex int, fitness T, globalposition []T) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if particleindex < 0 || particleindex >= len(s.particles) {
+		return
+	}
+	p := &s.particles[particleindex]
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if best, global := s.informantbest(particleindex); !global {
+		globalposition = best
+	} else if len(globalposition) != len(s.globalposition) || globalposition == nil {
+		globalposition = make([]T, len(s.globalposition))
+		copy(globalposition, s.globalposition)
+	}
+	s.move(particleindex, globalposition)
+}
+
+//isbetter returns true if the swarm has no global best yet or r is better than it.
+func (s *Swarm[T]) isbetter(r Record[T]) bool {
+	return !s.hasbest || s.better(r, s.record())
+}
+
+//record returns the record of the global best.
+func (s *Swarm[T]) record() Record[T] {
+	return Record[T]{Fitness: s.fitness, Violation: s.violation, Keys: s.keys}
+}
+
+//setbest makes r the global best.  position is the position r was evaluated at.
+func (s *Swarm[T]) setbest(r Record[T], position []T) {
+	s.hasbest = true
+	s.fitness = r.Fitness
+	s.violation = r.Violation
+	s.keys = append(s.keys[:0:0], r.Keys...)
+	copy(s.globalposition, position)
+}
+
+//clearbest forgets the global best.
+func (s *Swarm[T]) clearbest() {
+	s.hasbest = false
+	s.fitness = 0
+	s.violation = T(math.Inf(1))
+	s.keys = nil
+	for i := range s.globalposition {
+		s.globalposition[i] = 0
+	}
+}
+
+//updateconstraint lets the constraint handler know an update happened.
+func (s *Swarm[T]) updateconstraint() {
+	if s.constraint != nil {
+		s.constraint.Update(s.record(), s.k)
+	}
+}
@@ -0,0 +1,245 @@
+package pso
+
+import (
+	"sync"
+	"testing"
+)
+
+//TestMinMaxSymmetry checks that maximizing -f moves the swarm exactly like minimizing f for every Mode and update path.
+func TestMinMaxSymmetry(t *testing.T) {
+	f := func(x []float64) float64 {
+		var sum float64
+		for _, v := range x {
+			sum += (v - 1) * (v - 1)
+		}
+		return sum
+	}
+	neg := func(x []float64) float64 { return -f(x) }
+	var m Mode
+	modes := []Mode{m.Vanilla(), m.ConstantInertia(), m.InertiaReduction(), m.Constriction(), m.DynamicInertiaMaxVelReduction()}
+	paths := []string{"Optimize", "SyncUpdate", "SyncUpdateMultiThread", "AsyncUpdate", "IndvSyncUpdatePart2"}
+	for _, mode := range modes {
+		for _, path := range paths {
+			t.Run(mode.String()+"/"+path, func(t *testing.T) {
+				var positions [2][][]float64
+				var fitness [2]float64
+				for k, max := range []bool{false, true} {
+					s := CreateSwarm64(7)
+					err := s.GenericSet(mode, 10, 3, 2.05, 2.05, 1, -5, 5, .9, .9)
+					if err != nil {
+						t.Fatal(err)
+					}
+					objective := f
+					if max {
+						objective = neg
+					}
+					if path == "Optimize" {
+						budget := Budget64{MaxIterations: 30}
+						if max {
+							_, err = s.Maximize(objective, budget)
+						} else {
+							_, err = s.Minimize(objective, budget)
+						}
+						if err != nil {
+							t.Fatal(err)
+						}
+					} else {
+						s.SetFitness(max)
+						for it := 0; it < 30; it++ {
+							update(t, s, path, objective)
+						}
+					}
+					positions[k] = s.positions()
+					fitness[k] = s.GlobalFitness()
+				}
+				if fitness[0] != -fitness[1] {
+					t.Fatalf("minimized fitness %v, maximized %v", fitness[0], fitness[1])
+				}
+				for i := range positions[0] {
+					for j := range positions[0][i] {
+						if positions[0][i][j] != positions[1][i][j] {
+							t.Fatalf("particle %d is at %v minimizing and %v maximizing", i, positions[0][i], positions[1][i])
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+//update does one update of s with objective through path.
+func update(t *testing.T, s *Swarm64, path string, objective func([]float64) float64) {
+	positions := s.positions()
+	fitnesses := make([]float64, len(positions))
+	for i := range positions {
+		fitnesses[i] = objective(positions[i])
+	}
+	var err error
+	switch path {
+	case "SyncUpdate":
+		err = s.SyncUpdate(fitnesses)
+	case "SyncUpdateMultiThread":
+		err = s.SyncUpdateMultiThread(fitnesses)
+	case "AsyncUpdate":
+		for i := range positions {
+			err = s.AsyncUpdate(i, objective(s.ParticlePosition(i)))
+			if err != nil {
+				break
+			}
+		}
+	case "IndvSyncUpdatePart2":
+		for i := range fitnesses {
+			s.IndvSyncUpdatePart1(i, fitnesses[i])
+		}
+		s.IndvSyncUpdatePart2(fitnesses)
+		for i := range fitnesses {
+			s.IndvSyncUpdatePart3(i, fitnesses[i], nil)
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+//TestResetGlobalBest checks that ResetParticles with resetglobalposition forgets the global best so a worse one can replace it.
+func TestResetGlobalBest(t *testing.T) {
+	for _, max := range []bool{false, true} {
+		s := CreateSwarm64(1)
+		err := s.SetConstantInertia(2, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetFitness(max)
+		good, bad := 1.0, 50.0
+		if max {
+			good, bad = -good, -bad
+		}
+		err = s.SyncUpdate([]float64{good, good})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.ResetParticles([]FitnessIndex64{{Particle: 0}, {Particle: 1}}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.HasBest() {
+			t.Fatal("HasBest is true after the global best was reset")
+		}
+		err = s.SyncUpdate([]float64{bad, bad})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f := s.GlobalFitness(); f != bad {
+			t.Fatalf("global fitness is %v after the reset, want %v", f, bad)
+		}
+	}
+}
+
+//TestSeed checks that the same seed gives the same run, another seed gives another one,
+//and that SyncUpdateMultiThread moves the swarm exactly like SyncUpdate.
+func TestSeed(t *testing.T) {
+	run := func(seed int, multithread bool) *Swarm64 {
+		s := CreateSwarm64(seed)
+		err := s.SetConstantInertia(16, 4, 1.49445, 1.49445, 1, -5, 5, .7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k := 0; k < 25; k++ {
+			fitnesses := make([]float64, len(s.particles))
+			for i := range s.particles {
+				for _, v := range s.particles[i].position {
+					fitnesses[i] += v * v
+				}
+			}
+			if multithread {
+				err = s.SyncUpdateMultiThread(fitnesses)
+			} else {
+				err = s.SyncUpdate(fitnesses)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return s
+	}
+	same := func(a, b *Swarm64) bool {
+		for i := range a.particles {
+			if !equal(a.particles[i].position, b.particles[i].position) {
+				return false
+			}
+		}
+		return true
+	}
+	want := run(3, false)
+	if !same(run(3, false), want) {
+		t.Fatal("two runs with seed 3 ended in different places")
+	}
+	if !same(run(3, true), want) {
+		t.Fatal("SyncUpdateMultiThread ended somewhere else than SyncUpdate")
+	}
+	if same(run(4, false), want) {
+		t.Fatal("seeds 3 and 4 ended in the same place")
+	}
+}
+
+//TestConcurrentUse hammers a swarm from several goroutines.  Run it with -race.
+func TestConcurrentUse(t *testing.T) {
+	const dims = 4
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(20, dims, 1.49445, 1.49445, 1, -5, 5, .9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTopology(RandomInformants(3))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 300; n++ {
+				i := (n + g) % 25
+				switch (n + g) % 8 {
+				case 0:
+					s.AsyncUpdate(i, float64(n%17))
+				case 1:
+					s.AddParticles(1)
+				case 2:
+					if s.NumOfParticles() > 10 {
+						s.KillParticles([]FitnessIndex64{{Particle: i % 10}})
+					}
+				case 3:
+					s.GlobalPosition()
+					s.GlobalFitness()
+					s.ParticlePosition(i)
+					s.ParticleFitness(i)
+					s.AllFitnesses(nil)
+				case 4:
+					s.IndvSyncUpdatePart1(i, float64(n%13))
+				case 5:
+					s.IndvSyncUpdatePart2([]float64{float64(n % 11), 3})
+				case 6:
+					s.IndvSyncUpdatePart3(i, 0, nil)
+				case 7:
+					s.AsyncUpdate(-1, 0)
+					s.ResetParticle(i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	n := s.NumOfParticles()
+	if n < 10 {
+		t.Fatalf("%d particles left, want at least 10", n)
+	}
+	if f := s.AllFitnesses(nil); len(f) != n {
+		t.Fatalf("AllFitnesses returned %d, want %d", len(f), n)
+	}
+	for i := 0; i < n; i++ {
+		if p := s.ParticlePosition(i); len(p) != dims {
+			t.Fatalf("particle %d has %d dims, want %d", i, len(p), dims)
+		}
+	}
+	if !s.HasBest() || len(s.GlobalPosition()) != dims {
+		t.Fatal("swarm has no global best after updates")
+	}
+}
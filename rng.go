@@ -0,0 +1,37 @@
+package pso
+
+import (
+	randv2 "math/rand/v2"
+)
+
+//source is the rand.Source used by the swarm and its particles. Unlike the source from rand.NewSource
+//its state can be saved and loaded, so a checkpointed swarm picks up the same random numbers.
+type source struct {
+	pcg *randv2.PCG
+}
+
+const sourceincrement = 0x9e3779b97f4a7c15
+
+func newsource(seed int64) *source {
+	return &source{pcg: randv2.NewPCG(uint64(seed), sourceincrement)}
+}
+
+func (s *source) Int63() int64 {
+	return int64(s.pcg.Uint64() >> 1)
+}
+
+func (s *source) Uint64() uint64 {
+	return s.pcg.Uint64()
+}
+
+func (s *source) Seed(seed int64) {
+	s.pcg.Seed(uint64(seed), sourceincrement)
+}
+
+func (s *source) MarshalBinary() ([]byte, error) {
+	return s.pcg.MarshalBinary()
+}
+
+func (s *source) UnmarshalBinary(data []byte) error {
+	return s.pcg.UnmarshalBinary(data)
+}
@@ -0,0 +1,367 @@
+package pso
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+//Kind is the flag for the type of a search space Dimension.
+type Kind int32
+
+//Continuous sets Continuous Kind.  Values are float64s spread evenly between Min and Max.
+func (k *Kind) Continuous() Kind { *k = Kind(1); return *k }
+
+//Integer sets Integer Kind.  Values are ints from Min to Max with each one getting the same share of the dimension.
+func (k *Kind) Integer() Kind { *k = Kind(2); return *k }
+
+//LogUniform sets LogUniform Kind.  Values are float64s spread evenly between log(Min) and log(Max).  Good for things like learning rates.
+func (k *Kind) LogUniform() Kind { *k = Kind(3); return *k }
+
+//Categorical sets Categorical Kind.  Values are one of Choices with each one getting the same share of the dimension.
+func (k *Kind) Categorical() Kind { *k = Kind(4); return *k }
+
+func (k Kind) String() string {
+	var f Kind
+	switch k {
+	case f.Continuous():
+		return "Continuous"
+	case f.Integer():
+		return "Integer"
+	case f.LogUniform():
+		return "LogUniform"
+	case f.Categorical():
+		return "Categorical"
+	}
+	return "None"
+}
+
+//Dimension is one named parameter of a SearchSpace.  Make them with ContinuousDim, IntegerDim, LogUniformDim and CategoricalDim.
+//
+//If Parent is set the dimension is only active when the parent is active and Active returns true for the parent's value.
+//Use When or WhenFunc to set them.
+type Dimension struct {
+	Name     string
+	Kind     Kind
+	Min, Max float64
+	Choices  []any
+	Parent   string
+	Active   func(parent any) bool
+}
+
+//When returns d made conditional on parent.  d is only active when parent decodes to one of values.
+//
+//Like momentum only mattering for sgd:
+//
+//	CategoricalDim("optimizer", "sgd", "adam")
+//	ContinuousDim("momentum", 0, .99).When("optimizer", "sgd")
+func (d Dimension) When(parent string, values ...any) Dimension {
+	d.Parent = parent
+	d.Active = func(v any) bool {
+		for _, value := range values {
+			if reflect.DeepEqual(v, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return d
+}
+
+//WhenFunc returns d made conditional on parent.  d is only active when active returns true for the parent's value.
+//
+//Like the width of layer 3 only mattering with 3 or more layers:
+//
+//	IntegerDim("width3", 16, 512).WhenFunc("layers", func(v any) bool { return v.(int) >= 3 })
+func (d Dimension) WhenFunc(parent string, active func(v any) bool) Dimension {
+	d.Parent = parent
+	d.Active = active
+	return d
+}
+
+//ContinuousDim returns a Continuous Dimension from min to max.
+func ContinuousDim(name string, min, max float64) Dimension {
+	var k Kind
+	return Dimension{Name: name, Kind: k.Continuous(), Min: min, Max: max}
+}
+
+//IntegerDim returns an Integer Dimension from min to max.  Both are included.
+func IntegerDim(name string, min, max int) Dimension {
+	var k Kind
+	return Dimension{Name: name, Kind: k.Integer(), Min: float64(min), Max: float64(max)}
+}
+
+//LogUniformDim returns a LogUniform Dimension from min to max.  min needs to be > 0.
+func LogUniformDim(name string, min, max float64) Dimension {
+	var k Kind
+	return Dimension{Name: name, Kind: k.LogUniform(), Min: min, Max: max}
+}
+
+//CategoricalDim returns a Categorical Dimension that picks one of choices.
+func CategoricalDim(name string, choices ...any) Dimension {
+	var k Kind
+	return Dimension{Name: name, Kind: k.Categorical(), Choices: choices}
+}
+
+//decode turns u in [0,1] into the value of the dimension.
+func (d Dimension) decode(u float64) any {
+	u = math.Min(math.Max(u, 0), 1)
+	var k Kind
+	switch d.Kind {
+	case k.Integer():
+		return int(math.Min(d.Min+math.Floor(u*(d.Max-d.Min+1)), d.Max))
+	case k.LogUniform():
+		return math.Exp(math.Log(d.Min) + u*(math.Log(d.Max)-math.Log(d.Min)))
+	case k.Categorical():
+		return d.Choices[int(math.Min(math.Floor(u*float64(len(d.Choices))), float64(len(d.Choices)-1)))]
+	}
+	return d.Min + u*(d.Max-d.Min)
+}
+
+//SearchSpace maps named, typed parameters onto a swarm.
+//
+//The swarm works on every dimension from 0 to 1 and SearchSpace decodes those positions back to typed values.
+//Because every dimension is the same width to the swarm, vmax is a fraction of each dimension's range no matter its type or scale.
+//
+//Conditional dimensions that are inactive are left out of Decode, and a particle's inactive dimensions keep their
+//position and have their velocity zeroed when the particle is moved so they don't drift while they don't matter.
+type SearchSpace struct {
+	dims        []Dimension
+	parents     []int
+	conditional bool
+}
+
+//NewSearchSpace returns a SearchSpace of dims.  Dimension i of the swarm is dims[i].
+func NewSearchSpace(dims ...Dimension) (*SearchSpace, error) {
+	if len(dims) == 0 {
+		return nil, errors.New("Search space needs at least one dimension")
+	}
+	var k Kind
+	sp := &SearchSpace{dims: append([]Dimension(nil), dims...), parents: make([]int, len(dims))}
+	names := make(map[string]int)
+	for i, d := range dims {
+		if d.Name == "" {
+			return nil, errors.New("Dimension needs a name")
+		}
+		if _, ok := names[d.Name]; ok {
+			return nil, fmt.Errorf("Dimension %s is used more than once", d.Name)
+		}
+		sp.parents[i] = -1
+		if d.Parent != "" {
+			p, ok := names[d.Parent]
+			if !ok {
+				return nil, fmt.Errorf("Parent %s of dimension %s needs to come before it", d.Parent, d.Name)
+			}
+			if d.Active == nil {
+				return nil, fmt.Errorf("Dimension %s has a parent but no Active func", d.Name)
+			}
+			sp.parents[i] = p
+			sp.conditional = true
+		}
+		names[d.Name] = i
+		switch d.Kind {
+		case k.Continuous(), k.Integer():
+			if !(d.Min < d.Max) {
+				return nil, fmt.Errorf("Dimension %s needs min < max", d.Name)
+			}
+		case k.LogUniform():
+			if !(0 < d.Min && d.Min < d.Max) {
+				return nil, fmt.Errorf("Dimension %s needs 0 < min < max", d.Name)
+			}
+		case k.Categorical():
+			if len(d.Choices) == 0 {
+				return nil, fmt.Errorf("Dimension %s needs at least one choice", d.Name)
+			}
+		default:
+			return nil, fmt.Errorf("Dimension %s has no kind", d.Name)
+		}
+	}
+	return sp, nil
+}
+
+//Dims returns the number of dimensions the swarm needs for the search space.
+func (sp *SearchSpace) Dims() int {
+	return len(sp.dims)
+}
+
+//Dimensions returns a copy of the dimensions of the search space.
+func (sp *SearchSpace) Dimensions() []Dimension {
+	return append([]Dimension(nil), sp.dims...)
+}
+
+//Decode returns the value of each active dimension for position keyed by name.
+//Continuous and LogUniform values are float64, Integer values are int and Categorical values are one of the choices.
+func (sp *SearchSpace) Decode(position []float64) (map[string]any, error) {
+	if len(position) != len(sp.dims) {
+		return nil, errors.New("Length of position not the same as search space dims")
+	}
+	values := make(map[string]any, len(sp.dims))
+	for i, active := range sp.active(position) {
+		if active {
+			values[sp.dims[i].Name] = sp.dims[i].decode(position[i])
+		}
+	}
+	return values, nil
+}
+
+//ActiveDims returns which dimensions are active for position.
+func (sp *SearchSpace) ActiveDims(position []float64) ([]bool, error) {
+	if len(position) != len(sp.dims) {
+		return nil, errors.New("Length of position not the same as search space dims")
+	}
+	return sp.active(position), nil
+}
+
+//active returns which dimensions are active for position.  Parents come before their children so one pass is enough.
+func (sp *SearchSpace) active(position []float64) []bool {
+	active := make([]bool, len(sp.dims))
+	for i, d := range sp.dims {
+		p := sp.parents[i]
+		active[i] = p < 0 || (active[p] && d.Active(sp.dims[p].decode(position[p])))
+	}
+	return active
+}
+
+//DecodeInto decodes position into the struct v points to.
+//
+//A dimension goes into the field with a `pso:"name"` tag, or else the field with the same name ignoring case.
+//The value needs to be convertible to the field's type.  Fields without a dimension or of inactive dimensions are left alone.
+func (sp *SearchSpace) DecodeInto(position []float64, v any) error {
+	values, err := sp.Decode(position)
+	if err != nil {
+		return err
+	}
+	return fill(values, sp.dims, v)
+}
+
+//fill sets the fields of the struct v points to from values in the order of dims.
+func fill(values map[string]any, dims []Dimension, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("DecodeInto needs a pointer to a struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for _, d := range dims {
+		name := d.Name
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		field := -1
+		for i := 0; i < rt.NumField(); i++ {
+			f := rt.Field(i)
+			if tag, ok := f.Tag.Lookup("pso"); ok {
+				if tag == name {
+					field = i
+					break
+				}
+				continue
+			}
+			if field < 0 && strings.EqualFold(f.Name, name) {
+				field = i
+			}
+		}
+		if field < 0 {
+			return fmt.Errorf("No field for dimension %s", name)
+		}
+		fv := rv.Field(field)
+		if !fv.CanSet() {
+			return fmt.Errorf("Field for dimension %s can't be set", name)
+		}
+		val := reflect.ValueOf(value)
+		if !val.IsValid() {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+		if !val.Type().ConvertibleTo(fv.Type()) {
+			return fmt.Errorf("Dimension %s is %s and can't go into a field of %s", name, val.Type(), fv.Type())
+		}
+		fv.Set(val.Convert(fv.Type()))
+	}
+	return nil
+}
+
+//SetSearchSpace maps space onto the swarm.  Call it after one of the Set methods with dims equal to space.Dims() and before the first update.
+//
+//Every dimension is bounded to [0,1] and the particles are restarted evenly over it, so Integer and Categorical values
+//start out equally likely and LogUniform values start out evenly spread over their orders of magnitude.
+//The Boundary set with SetBoundary still decides what happens at the edges.  The space is not saved with checkpoints,
+//and it is dropped if the swarm is set up again with a different number of dims.
+func (s *Swarm[T]) SetSearchSpace(space *SearchSpace) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if space == nil {
+		s.space = nil
+		return nil
+	}
+	dims := space.Dims()
+	if len(s.globalposition) != dims {
+		return errors.New("Swarm dims not the same as search space dims")
+	}
+	lower := make([]T, dims)
+	upper := make([]T, dims)
+	for i := range upper {
+		upper[i] = 1
+	}
+	err := s.setbounds(lower, upper)
+	if err != nil {
+		return err
+	}
+	setstart("MinStart", s.xminstart, []T{0})
+	setstart("MaxStart", s.xmaxstart, []T{1})
+	s.space = space
+	for i := range s.particles {
+		s.particles[i].reset(s.vmax, s.xminstart, s.xmaxstart, s.alphamax, s.inertiamax)
+	}
+	return nil
+}
+
+//SearchSpace returns the search space set with SetSearchSpace.  It is nil if none was set.
+func (s *Swarm[T]) SearchSpace() *SearchSpace {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.space
+}
+
+//Decode decodes a position of the swarm, like GlobalPosition or a ParticlePosition, with the swarm's search space.
+func (s *Swarm[T]) Decode(position []T) (map[string]any, error) {
+	space := s.SearchSpace()
+	if space == nil {
+		return nil, errors.New("Swarm has no search space")
+	}
+	return space.Decode(tofloat64(position))
+}
+
+//DecodeInto decodes a position of the swarm into the struct v points to.  See SearchSpace.DecodeInto.
+func (s *Swarm[T]) DecodeInto(position []T, v any) error {
+	space := s.SearchSpace()
+	if space == nil {
+		return errors.New("Swarm has no search space")
+	}
+	return space.DecodeInto(tofloat64(position), v)
+}
+
+//inactive returns the dimensions of position that are inactive in the swarm's search space.
+func (s *Swarm[T]) inactive(position []T) []int {
+	if s.space == nil || !s.space.conditional || s.space.Dims() != len(position) {
+		return nil
+	}
+	var inactive []int
+	for i, active := range s.space.active(tofloat64(position)) {
+		if !active {
+			inactive = append(inactive, i)
+		}
+	}
+	return inactive
+}
+
+func tofloat64[T Float](x []T) []float64 {
+	f := make([]float64, len(x))
+	for i := range x {
+		f[i] = float64(x[i])
+	}
+	return f
+}
@@ -0,0 +1,226 @@
+package pso
+
+import (
+	"math"
+	"testing"
+)
+
+//TestDecode checks that each Kind of Dimension decodes the [0,1] positions of the swarm to its values.
+func TestDecode(t *testing.T) {
+	space, err := NewSearchSpace(
+		ContinuousDim("rate", 0, 10),
+		IntegerDim("layers", 1, 4),
+		LogUniformDim("c", 1e-3, 10),
+		CategoricalDim("activation", "relu", "tanh", "sigmoid"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		position []float64
+		rate     float64
+		layers   int
+		c        float64
+		act      string
+	}{
+		{[]float64{.25, .5, .5, .4}, 2.5, 3, .1, "tanh"},
+		{[]float64{0, 0, 0, 0}, 0, 1, 1e-3, "relu"},
+		{[]float64{1, 1, 1, 1}, 10, 4, 10, "sigmoid"},
+		{[]float64{-1, .99, 2, .99}, 0, 4, 10, "sigmoid"},
+	} {
+		values, err := space.Decode(c.position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if values["rate"] != c.rate || values["layers"] != c.layers || math.Abs(values["c"].(float64)-c.c) > 1e-12 || values["activation"] != c.act {
+			t.Fatalf("%v decoded to %v, want rate %v, layers %v, c %v and activation %v", c.position, values, c.rate, c.layers, c.c, c.act)
+		}
+		var v struct {
+			Rate       float32
+			Depth      int64 `pso:"layers"`
+			C          float64
+			Activation string
+		}
+		err = space.DecodeInto(c.position, &v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Rate != float32(c.rate) || v.Depth != int64(c.layers) || v.C != values["c"] || v.Activation != c.act {
+			t.Fatalf("%v decoded into %+v", c.position, v)
+		}
+	}
+	if _, err = space.Decode([]float64{0, 0}); err == nil {
+		t.Fatal("Decode of a position with the wrong length returned no error")
+	}
+	var bad struct{ Rate string }
+	if err = space.DecodeInto([]float64{0, 0, 0, 0}, &bad); err == nil {
+		t.Fatal("DecodeInto a string field for a float returned no error")
+	}
+}
+
+//TestNewSearchSpaceErrors checks the dimensions NewSearchSpace refuses.
+func TestNewSearchSpaceErrors(t *testing.T) {
+	for _, dims := range [][]Dimension{
+		nil,
+		{ContinuousDim("", 0, 1)},
+		{ContinuousDim("x", 0, 1), IntegerDim("x", 0, 1)},
+		{ContinuousDim("x", 1, 1)},
+		{LogUniformDim("x", 0, 1)},
+		{CategoricalDim("x")},
+		{{Name: "x"}},
+	} {
+		if _, err := NewSearchSpace(dims...); err == nil {
+			t.Fatalf("NewSearchSpace(%v) returned no error", dims)
+		}
+	}
+}
+
+//TestSearchSpaceSwarm checks that a swarm with a search space stays in [0,1] and its best decodes to the best values.
+func TestSearchSpaceSwarm(t *testing.T) {
+	space, err := NewSearchSpace(ContinuousDim("x", -5, 5), IntegerDim("n", 0, 9), CategoricalDim("kind", "a", "b", "c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := CreateSwarm64(1)
+	err = s.SetConstantInertia(20, 3, 1.49445, 1.49445, .2, 0, 1, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.SetSearchSpace(space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objective := func(x []float64) float64 {
+		values, err := space.Decode(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := math.Abs(values["x"].(float64)-1) + math.Abs(float64(values["n"].(int)-7))
+		if values["kind"] != "b" {
+			f += 10
+		}
+		return f
+	}
+	_, err = s.Minimize(objective, Budget64{MaxIterations: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range s.particles {
+		for _, v := range s.particles[i].position {
+			if v < 0 || v > 1 {
+				t.Fatalf("particle is at %v, outside of [0,1]", s.particles[i].position)
+			}
+		}
+	}
+	values, err := s.Decode(s.GlobalPosition())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["n"] != 7 || values["kind"] != "b" || math.Abs(values["x"].(float64)-1) > .1 {
+		t.Fatalf("best decoded to %v, want x near 1, n 7 and kind b", values)
+	}
+}
+
+//TestConditional checks which dimensions are active, that inactive ones are left out of Decode and that particles
+//don't move in their inactive dimensions.
+func TestConditional(t *testing.T) {
+	space, err := NewSearchSpace(
+		CategoricalDim("kind", "a", "b"),
+		ContinuousDim("y", 0, 1).When("kind", "b"),
+		ContinuousDim("z", 0, 1).WhenFunc("y", func(v any) bool { return v.(float64) > .5 }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		position []float64
+		want     []bool
+	}{
+		{[]float64{.2, .9, .5}, []bool{true, false, false}},
+		{[]float64{.8, .9, .5}, []bool{true, true, true}},
+		{[]float64{.8, .1, .5}, []bool{true, true, false}},
+	} {
+		active, err := space.ActiveDims(c.position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values, err := space.Decode(c.position)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, d := range space.Dimensions() {
+			if _, ok := values[d.Name]; active[i] != c.want[i] || ok != c.want[i] {
+				t.Fatalf("%v: active dims are %v and decoded to %v, want %v", c.position, active, values, c.want)
+			}
+		}
+	}
+	if _, err = NewSearchSpace(ContinuousDim("y", 0, 1).When("kind", "b"), CategoricalDim("kind", "a", "b")); err == nil {
+		t.Fatal("NewSearchSpace with a child before its parent returned no error")
+	}
+
+	s := CreateSwarm64(1)
+	err = s.SetConstantInertia(20, 3, 1.49445, 1.49445, .3, 0, 1, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.SetSearchSpace(space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k < 10; k++ {
+		before := make([][]float64, len(s.particles))
+		for i := range s.particles {
+			before[i] = append([]float64(nil), s.particles[i].position...)
+		}
+		fitnesses := make([]float64, len(before))
+		for i, x := range before {
+			fitnesses[i] = x[0] + x[1] + x[2]
+		}
+		err = s.SyncUpdate(fitnesses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range before {
+			after := s.particles[i].position
+			active, _ := space.ActiveDims(before[i])
+			for j := range active {
+				if !active[j] && before[i][j] != after[j] {
+					t.Fatalf("particle %d moved in inactive dimension %d from %v to %v", i, j, before[i], after)
+				}
+			}
+		}
+	}
+}
+
+//TestSearchSpaceReconfigure checks that setting a swarm up again with fewer dims drops its conditional search space
+//instead of panicking on the next update.
+func TestSearchSpaceReconfigure(t *testing.T) {
+	space, err := NewSearchSpace(
+		CategoricalDim("kind", "a", "b"),
+		ContinuousDim("x", 0, 1),
+		ContinuousDim("y", 0, 1).When("kind", "b"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := CreateSwarm64(1)
+	err = s.SetConstantInertia(4, 3, 1.49445, 1.49445, .2, 0, 1, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.SetSearchSpace(space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.SetConstantInertia(4, 2, 1.49445, 1.49445, .2, 0, 1, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.SearchSpace() != nil {
+		t.Fatal("search space kept after the dims changed")
+	}
+	err = s.SyncUpdate([]float64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
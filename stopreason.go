@@ -0,0 +1,31 @@
+package pso
+
+//StopReason is the flag that tells why an optimization run stopped
+type StopReason int32
+
+//MaxIterations sets StopReason to MaxIterations.  The run used up all of its iterations.
+func (s *StopReason) MaxIterations() StopReason { *s = StopReason(1); return *s }
+
+//MaxEvaluations sets StopReason to MaxEvaluations.  Another iteration would have gone over the evaluation budget.
+func (s *StopReason) MaxEvaluations() StopReason { *s = StopReason(2); return *s }
+
+//Target sets StopReason to Target.  The global fitness reached the target fitness.
+func (s *StopReason) Target() StopReason { *s = StopReason(3); return *s }
+
+//MaxTime sets StopReason to MaxTime. The run went over its wall clock limit.
+func (s *StopReason) MaxTime() StopReason { *s = StopReason(4); return *s }
+
+func (s StopReason) String() string {
+	var f StopReason
+	switch s {
+	case f.MaxIterations():
+		return "MaxIterations"
+	case f.MaxEvaluations():
+		return "MaxEvaluations"
+	case f.Target():
+		return "Target"
+	case f.MaxTime():
+		return "MaxTime"
+	}
+	return "None"
+}
@@ -0,0 +1,193 @@
+package pso
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+)
+
+//Topology decides which particles inform each other.  Instead of being pulled toward the global best,
+//each particle is pulled toward the best personal best of its informants.
+//
+//GlobalPosition and GlobalFitness still report the best of the whole swarm.
+//
+//The built in topologies with state are copied when they are given to a swarm, so one can be used for many swarms.
+//A user defined Topology that changes in Update needs its own value for every swarm.
+type Topology interface {
+	//Informants returns the indexes of the particles that inform particle i in a swarm of n particles.
+	//Particle i always informs itself, so it doesn't need to be returned.
+	//Returning nil means particle i is informed by the whole swarm and the global best is used.
+	Informants(i, n int) []int
+
+	//Update is called once after every swarm update before the particles are moved.
+	//improved is true if the global best got better with that update.
+	Update(n int, improved bool, rng *rand.Rand)
+}
+
+//SetTopology sets the neighborhood topology of the swarm.  nil or Star() is the default where every particle follows the global best.
+func (s *Swarm[T]) SetTopology(t Topology) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.topology = fresh(t)
+}
+
+//fresh returns t without its state so swarms given the same Topology don't share its links.
+func fresh(t Topology) Topology {
+	if r, ok := t.(*randominformants); ok {
+		return &randominformants{k: r.k}
+	}
+	return t
+}
+
+//informantbest returns the best personal best of the informants of particle i.
+//If particle i follows the global best then global will be true and the global position is returned.
+func (s *Swarm[T]) informantbest(i int) (best []T, global bool) {
+	if s.topology == nil {
+		return s.globalposition, true
+	}
+	informants := s.topology.Informants(i, len(s.particles))
+	if informants == nil {
+		return s.globalposition, true
+	}
+	b := i
+	for _, j := range informants {
+		if j < 0 || j >= len(s.particles) {
+			continue
+		}
+		if s.particles[j].hasbest && (!s.particles[b].hasbest || s.better(s.particles[j].record(), s.particles[b].record())) {
+			b = j
+		}
+	}
+	return s.particles[b].indvbest, false
+}
+
+//updatetopology lets the topology know an update happened.
+func (s *Swarm[T]) updatetopology(improved bool) {
+	if s.topology != nil {
+		s.topology.Update(len(s.particles), improved, s.rng)
+	}
+}
+
+type star struct{}
+
+//Star returns the star (gbest) topology. Every particle is informed by the whole swarm. This is the default.
+func Star() Topology { return star{} }
+
+func (star) Informants(i, n int) []int                   { return nil }
+func (star) Update(n int, improved bool, rng *rand.Rand) {}
+
+type ring struct {
+	k int
+}
+
+//Ring returns the ring (lbest) topology. Each particle is informed by the k particles on each side of it.
+func Ring(k int) Topology {
+	if k < 1 {
+		k = 1
+	}
+	return ring{k: k}
+}
+
+func (r ring) Informants(i, n int) []int {
+	informants := make([]int, 0, 2*r.k)
+	for j := 1; j <= r.k && j < n; j++ {
+		informants = append(informants, (i+j)%n, ((i-j)%n+n)%n)
+	}
+	return informants
+}
+func (ring) Update(n int, improved bool, rng *rand.Rand) {}
+
+type vonneumann struct{}
+
+//VonNeumann returns the von Neumann topology. The particles are laid out on a wrapped grid and
+//each particle is informed by the particles above, below, left and right of it.
+func VonNeumann() Topology { return vonneumann{} }
+
+func (vonneumann) Informants(i, n int) []int {
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + cols - 1) / cols
+	r, c := i/cols, i%cols
+	informants := make([]int, 0, 4)
+	for _, rc := range [4][2]int{{r - 1, c}, {r + 1, c}, {r, c - 1}, {r, c + 1}} {
+		j := ((rc[0]+rows)%rows)*cols + (rc[1]+cols)%cols
+		if j < n && j != i {
+			informants = append(informants, j)
+		}
+	}
+	return informants
+}
+func (vonneumann) Update(n int, improved bool, rng *rand.Rand) {}
+
+type randominformants struct {
+	k          int
+	informants [][]int
+}
+
+//RandomInformants returns the adaptive random topology from SPSO-2011.  Each particle informs itself and k random particles.
+//The links are made again after every update where the global best didn't improve.
+func RandomInformants(k int) Topology {
+	if k < 1 {
+		k = 1
+	}
+	return &randominformants{k: k}
+}
+
+func (r *randominformants) Informants(i, n int) []int {
+	if len(r.informants) != n {
+		return nil
+	}
+	return r.informants[i]
+}
+
+func (r *randominformants) Update(n int, improved bool, rng *rand.Rand) {
+	if improved && len(r.informants) == n {
+		return
+	}
+	r.informants = make([][]int, n)
+	for i := range r.informants {
+		r.informants[i] = []int{}
+	}
+	for j := 0; j < n; j++ {
+		for l := 0; l < r.k; l++ {
+			i := rng.Intn(n)
+			r.informants[i] = append(r.informants[i], j)
+		}
+	}
+}
+
+//MarshalBinary saves the current links so checkpoints can be restarted exactly.
+func (r *randominformants) MarshalBinary() ([]byte, error) {
+	return json.Marshal(r.informants)
+}
+
+//UnmarshalBinary loads links saved with MarshalBinary.
+func (r *randominformants) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &r.informants)
+}
+
+type graph struct {
+	adjacency [][]int
+}
+
+//Graph returns a user defined topology.  adjacency[i] holds the indexes of the particles that inform particle i.
+//
+//adjacency needs a row for every particle in the swarm.  Particles past the last row follow the global best.
+func Graph(adjacency [][]int) (Topology, error) {
+	for i := range adjacency {
+		for _, j := range adjacency[i] {
+			if j < 0 || j >= len(adjacency) {
+				return nil, errors.New("Adjacency index out of bounds")
+			}
+		}
+	}
+	return graph{adjacency: adjacency}, nil
+}
+
+func (g graph) Informants(i, n int) []int {
+	if i >= len(g.adjacency) {
+		return nil
+	}
+	return g.adjacency[i]
+}
+func (graph) Update(n int, improved bool, rng *rand.Rand) {}
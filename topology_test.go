@@ -0,0 +1,115 @@
+package pso
+
+import (
+	"math/rand"
+	"testing"
+)
+
+//TestInformants checks the informants of the built in topologies.
+func TestInformants(t *testing.T) {
+	g, err := Graph([][]int{{1}, {0, 2}, {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name string
+		t    Topology
+		i, n int
+		want []int
+	}{
+		{"Star", Star(), 2, 5, nil},
+		{"Ring(1)", Ring(1), 0, 5, []int{1, 4}},
+		{"Ring(2)", Ring(2), 3, 5, []int{4, 2, 0, 1}},
+		{"VonNeumann center", VonNeumann(), 4, 9, []int{1, 7, 3, 5}},
+		{"VonNeumann corner", VonNeumann(), 0, 9, []int{6, 3, 2, 1}},
+		{"Graph", g, 1, 3, []int{0, 2}},
+		{"Graph past the rows", g, 3, 4, nil},
+	} {
+		got := c.t.Informants(c.i, c.n)
+		if (got == nil) != (c.want == nil) || len(got) != len(c.want) {
+			t.Fatalf("%s: informants of %d are %v, want %v", c.name, c.i, got, c.want)
+		}
+		for j := range got {
+			if got[j] != c.want[j] {
+				t.Fatalf("%s: informants of %d are %v, want %v", c.name, c.i, got, c.want)
+			}
+		}
+	}
+	if _, err = Graph([][]int{{1}, {2}}); err == nil {
+		t.Fatal("Graph with an index out of bounds returned no error")
+	}
+}
+
+//TestRandomInformants checks that RandomInformants gives every particle k links and only remakes them when the global best doesn't improve.
+func TestRandomInformants(t *testing.T) {
+	r := RandomInformants(3)
+	rng := rand.New(rand.NewSource(1))
+	if r.Informants(0, 10) != nil {
+		t.Fatal("informants before the first Update aren't nil")
+	}
+	r.Update(10, false, rng)
+	links := func() (links [][]int, total int) {
+		for i := 0; i < 10; i++ {
+			links = append(links, append([]int(nil), r.Informants(i, 10)...))
+			total += len(links[i])
+		}
+		return links, total
+	}
+	before, total := links()
+	if total != 30 {
+		t.Fatalf("%d links, want 30", total)
+	}
+	r.Update(10, true, rng)
+	after, _ := links()
+	for i := range before {
+		if !equalints(before[i], after[i]) {
+			t.Fatal("links changed after an update that improved the global best")
+		}
+	}
+	r.Update(10, false, rng)
+	after, _ = links()
+	same := true
+	for i := range before {
+		same = same && equalints(before[i], after[i])
+	}
+	if same {
+		t.Fatal("links didn't change after an update that didn't improve the global best")
+	}
+	if r.Informants(0, 11) != nil {
+		t.Fatal("informants for another number of particles aren't nil")
+	}
+}
+
+//TestRingFollowsNeighbors checks that with a Ring a particle follows the best of its neighbors instead of the global best.
+func TestRingFollowsNeighbors(t *testing.T) {
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(5, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetTopology(Ring(1))
+	err = s.SyncUpdate([]float64{5, 4, 3, 2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, c := range []struct{ i, want int }{{0, 4}, {2, 3}, {3, 4}} {
+		best, global := s.informantbest(c.i)
+		if global || !equal(best, s.particles[c.want].indvbest) {
+			t.Fatalf("particle %d follows %v, want the personal best of particle %d %v", c.i, best, c.want, s.particles[c.want].indvbest)
+		}
+	}
+}
+
+func equalints(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package pso
+
+import "math/rand"
+
+//ParticleState is what a VelocityUpdater gets when it updates a particle.
+//
+//Position and Velocity are the particle's own slices and are changed in place.
+//PersonalBest and InformantBest should only be read.  InformantBest is the global best unless a Topology is set.
+//Inertia and Alpha are written back to the particle after the update.
+type ParticleState[T Float] struct {
+	Position      []T
+	Velocity      []T
+	PersonalBest  []T
+	InformantBest []T
+	Inertia       T
+	Alpha         T
+	Cognative     T
+	Social        T
+	Vmax          T
+	Constriction  T
+	Rng           *rand.Rand
+	Iteration     int
+}
+
+//VelocityUpdater updates the velocity and position of one particle.  Pass one to SetUpdater to use a custom update rule.
+//
+//Update is called from multiple goroutines in SyncUpdateMultiThread, each with a different particle.
+//Use p.Rng for random numbers so runs stay reproducible.
+type VelocityUpdater[T Float] interface {
+	Update(p *ParticleState[T])
+}
+
+//SetUpdater sets the swarm to Custom mode and uses u to update the particles.
+func (s *Swarm[T]) SetUpdater(u VelocityUpdater[T]) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.custom = u
+	s.updater = u
+	s.mode.Custom()
+}
+
+//updaterfor returns the built in VelocityUpdater for mode.  It returns nil for Custom or unknown modes.
+func updaterfor[T Float](mode Mode) VelocityUpdater[T] {
+	var m Mode
+	switch mode {
+	case m.Vanilla():
+		return VanillaUpdater[T]{}
+	case m.ConstantInertia():
+		return ConstantInertiaUpdater[T]{}
+	case m.InertiaReduction():
+		return InertiaReductionUpdater[T]{}
+	case m.Constriction():
+		return ConstrictionUpdater[T]{}
+	case m.DynamicInertiaMaxVelReduction():
+		return DynamicInertiaMaxVelReductionUpdater[T]{}
+	}
+	return nil
+}
+
+//VanillaUpdater is the VelocityUpdater used by Vanilla mode.
+type VanillaUpdater[T Float] struct{}
+
+//Update does the vanilla update.
+func (VanillaUpdater[T]) Update(p *ParticleState[T]) {
+	for i := range p.Velocity {
+		p.Velocity[i] += +p.Cognative*randf[T](p.Rng)*(p.PersonalBest[i]-p.Position[i]) + p.Social*randf[T](p.Rng)*(p.InformantBest[i]-p.Position[i])
+
+		p.Velocity[i] = minmagnitude(p.Velocity[i], p.Vmax)
+
+		p.Position[i] += p.Velocity[i]
+	}
+}
+
+//ConstantInertiaUpdater is the VelocityUpdater used by ConstantInertia mode.
+type ConstantInertiaUpdater[T Float] struct{}
+
+//Update does the constant inertia update.
+func (ConstantInertiaUpdater[T]) Update(p *ParticleState[T]) {
+	for i := range p.Velocity {
+		p.Velocity[i] = (p.Inertia * p.Velocity[i]) + (p.Cognative * randf[T](p.Rng) * (p.PersonalBest[i] - p.Position[i])) + (p.Social * randf[T](p.Rng) * (p.InformantBest[i] - p.Position[i]))
+
+		p.Velocity[i] = minmagnitude(p.Velocity[i], p.Vmax)
+		p.Position[i] += p.Velocity[i]
+	}
+}
+
+//InertiaReductionUpdater is the VelocityUpdater used by InertiaReduction mode.  Inertia is multiplied by alpha after each update.
+type InertiaReductionUpdater[T Float] struct{}
+
+//Update does the linear inertia reduction update.
+func (InertiaReductionUpdater[T]) Update(p *ParticleState[T]) {
+	for i := range p.Velocity {
+		p.Velocity[i] = (p.Inertia * p.Velocity[i]) + p.Cognative*randf[T](p.Rng)*(p.PersonalBest[i]-p.Position[i]) + p.Social*randf[T](p.Rng)*(p.InformantBest[i]-p.Position[i])
+		p.Velocity[i] = minmagnitude(p.Velocity[i], p.Vmax)
+		p.Position[i] += p.Velocity[i]
+	}
+	p.Inertia *= p.Alpha
+}
+
+//ConstrictionUpdater is the VelocityUpdater used by Constriction mode.
+type ConstrictionUpdater[T Float] struct{}
+
+//Update does the constriction update.
+func (ConstrictionUpdater[T]) Update(p *ParticleState[T]) {
+	for i := range p.Velocity {
+		p.Velocity[i] = p.Constriction * (p.Velocity[i] + p.Cognative*randf[T](p.Rng)*(p.PersonalBest[i]-p.Position[i]) + p.Social*randf[T](p.Rng)*(p.InformantBest[i]-p.Position[i]))
+
+		p.Velocity[i] = minmagnitude(p.Velocity[i], p.Vmax)
+		p.Position[i] += p.Velocity[i]
+	}
+}
+
+//DynamicInertiaMaxVelReductionUpdater is the VelocityUpdater used by DynamicInertiaMaxVelReduction mode.
+//Vmax is used as a gamma and the max velocity is gamma*(max(position)-min(position)).
+type DynamicInertiaMaxVelReductionUpdater[T Float] struct{}
+
+//Update does the dynamic inertia max velocity reduction update.
+func (DynamicInertiaMaxVelReductionUpdater[T]) Update(p *ParticleState[T]) {
+	min := T(999999999)
+	max := T(-99999999)
+	for i := range p.Velocity {
+		p.Velocity[i] = p.Inertia*p.Velocity[i] + p.Cognative*randf[T](p.Rng)*(p.PersonalBest[i]-p.Position[i]) + p.Social*randf[T](p.Rng)*(p.InformantBest[i]-p.Position[i])
+		if p.Position[i] < min {
+			min = p.Position[i]
+		}
+		if p.Position[i] > max {
+			max = p.Position[i]
+		}
+
+	}
+	vmax := p.Vmax * (max - min)
+	for i := range p.Velocity {
+
+		p.Velocity[i] = minmagnitude(p.Velocity[i], vmax)
+
+		p.Position[i] += p.Velocity[i]
+	}
+}
@@ -0,0 +1,52 @@
+package pso
+
+import "testing"
+
+//countingupdater is ConstantInertiaUpdater that counts its updates.
+type countingupdater struct {
+	ConstantInertiaUpdater[float64]
+	n int
+}
+
+func (u *countingupdater) Update(p *ParticleState[float64]) {
+	u.n++
+	u.ConstantInertiaUpdater.Update(p)
+}
+
+//TestCustomUpdater checks that the VelocityUpdater passed to SetUpdater moves every particle and is kept across mode changes.
+func TestCustomUpdater(t *testing.T) {
+	s := CreateSwarm64(1)
+	err := s.SetConstantInertia(5, 2, 1.49445, 1.49445, 1, -5, 5, .7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &countingupdater{}
+	s.SetUpdater(u)
+	update := func() {
+		err := s.SyncUpdate([]float64{1, 2, 3, 4, 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	update()
+	if u.n != 5 {
+		t.Fatalf("custom updater was called %d times, want 5", u.n)
+	}
+	var m Mode
+	err = s.ChangeMode(m.ConstantInertia())
+	if err != nil {
+		t.Fatal(err)
+	}
+	update()
+	if u.n != 5 {
+		t.Fatalf("custom updater was called %d times in ConstantInertia mode, want 5", u.n)
+	}
+	err = s.ChangeMode(m.Custom())
+	if err != nil {
+		t.Fatal(err)
+	}
+	update()
+	if u.n != 10 {
+		t.Fatalf("custom updater was called %d times after changing back to Custom, want 10", u.n)
+	}
+}